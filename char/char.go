@@ -16,8 +16,29 @@ const (
 	ENTER     = "<Enter>"
 	ESCAPE    = "<Escape>"
 	SPACE     = "<Space>"
+	PAGE_UP   = "<PageUp>"
+	PAGE_DOWN = "<PageDown>"
 
+	CTRL_A = "<C-a>"
+	CTRL_B = "<C-b>"
 	CTRL_C = "<C-c>"
+	CTRL_D = "<C-d>"
+	CTRL_E = "<C-e>"
 	CTRL_F = "<C-c>"
+	CTRL_G = "<C-g>"
+	CTRL_K = "<C-k>"
 	CTRL_L = "<C-l>"
+	CTRL_N = "<C-n>"
+	CTRL_O = "<C-o>"
+	CTRL_P = "<C-p>"
+	CTRL_Q = "<C-q>"
+	CTRL_R = "<C-r>"
+	CTRL_S = "<C-s>"
+	CTRL_T = "<C-t>"
+	CTRL_U = "<C-u>"
+	CTRL_V = "<C-v>"
+	CTRL_W = "<C-w>"
+	CTRL_X = "<C-x>"
+	CTRL_Y = "<C-y>"
+	CTRL_Z = "<C-z>"
 )
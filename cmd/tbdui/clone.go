@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// cloneUsage is the title of the clone prompt.
+const cloneUsage = "Clone - <environment> [region] [name=value ...], copies the current item, e.g. staging company_id=acme"
+
+// clonePlan is an item ready to be written to a table in some environment.
+type clonePlan struct {
+	conn   connection
+	schema dynamodb.TableSchema
+	item   dynamodb.Item
+}
+
+// planClone copies item from table into the same table in another environment, rewriting keys and attributes.
+// Input is "<environment> [region] [name=value ...]", and the current environment can be given to clone within it.
+func (ui TUI) planClone(ctx context.Context, input, table string, item dynamodb.Item) (clonePlan, error) {
+	place, overrides, err := parseClone(input)
+	if err != nil {
+		return clonePlan{}, err
+	}
+	environment, region, err := parseSwitch(strings.Join(place, " "), ui.region)
+	if err != nil {
+		return clonePlan{}, err
+	}
+
+	conn := ui.current()
+	if environment != ui.environment || region != ui.region {
+		if conn, err = ui.connect(environment, region); err != nil {
+			return clonePlan{}, err
+		}
+	}
+	target := ui.tableIn(conn.environment, table)
+	schema, err := conn.db.Schema(ctx, target)
+	if err != nil {
+		return clonePlan{}, err
+	}
+	clone, err := dynamodb.CloneItem(schema.KeySchema, item, overrides)
+	if err != nil {
+		return clonePlan{}, err
+	}
+	return clonePlan{conn: conn, schema: schema, item: clone}, nil
+}
+
+// parseClone splits clone input into the environment and region, and the overrides which follow them.  Words
+// without an "=" after the first override are part of its value, so values may contain spaces.
+func parseClone(input string) ([]string, []dynamodb.Override, error) {
+	var place []string
+	var overrides []dynamodb.Override
+	for _, word := range strings.Fields(input) {
+		if !strings.Contains(word, "=") {
+			if len(overrides) == 0 {
+				place = append(place, word)
+				continue
+			}
+			overrides[len(overrides)-1].Value += " " + word
+			continue
+		}
+		o, err := dynamodb.ParseOverride(word)
+		if err != nil {
+			return nil, nil, err
+		}
+		overrides = append(overrides, o)
+	}
+	return place, overrides, nil
+}
+
+// describe previews the clone before it is written.
+func (p clonePlan) describe() string {
+	return fmt.Sprintf("clone into %s in %s, only if no item has the same key\n%s",
+		p.schema.Name, location(p.conn.environment, p.conn.region), formatItem(p.item))
+}
+
+// tableIn names a table of the current environment as it is named in another environment.  Tables not named after
+// the current environment are taken to be named the same everywhere.
+func (ui TUI) tableIn(environment, table string) string {
+	prefix := ui.environment + "-"
+	if !strings.HasPrefix(table, prefix) {
+		return table
+	}
+	return environment + "-" + strings.TrimPrefix(table, prefix)
+}
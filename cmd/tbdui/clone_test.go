@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+func TestParseClone(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		input             string
+		expectedPlace     []string
+		expectedOverrides []dynamodb.Override
+		expectErr         bool
+	}{
+		{
+			name:          "environment",
+			input:         "staging",
+			expectedPlace: []string{"staging"},
+		},
+		{
+			name:              "environment, region and overrides",
+			input:             "prod eu-west-1 company_id=acme integration_id=int-001",
+			expectedPlace:     []string{"prod", "eu-west-1"},
+			expectedOverrides: []dynamodb.Override{{Name: "company_id", Value: "acme"}, {Name: "integration_id", Value: "int-001"}},
+		},
+		{
+			name:              "values with spaces",
+			input:             "dev name=Slack   Alerts  enabled=false",
+			expectedPlace:     []string{"dev"},
+			expectedOverrides: []dynamodb.Override{{Name: "name", Value: "Slack Alerts"}, {Name: "enabled", Value: "false"}},
+		},
+		{
+			name:              "values with equals signs",
+			input:             "dev query=a=b",
+			expectedPlace:     []string{"dev"},
+			expectedOverrides: []dynamodb.Override{{Name: "query", Value: "a=b"}},
+		},
+		{
+			name:      "override without a name",
+			input:     "dev =acme",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			place, overrides, err := parseClone(tt.input)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedPlace, place)
+			require.Equal(t, tt.expectedOverrides, overrides)
+		})
+	}
+}
+
+func TestTableIn(t *testing.T) {
+	t.Parallel()
+
+	ui := TUI{environment: "dev"}
+	require.Equal(t, "staging-integrations", ui.tableIn("staging", "dev-integrations"))
+	require.Equal(t, "dev-integrations", ui.tableIn("dev", "dev-integrations"))
+	// tables not named after the current environment keep their names
+	require.Equal(t, "shared", ui.tableIn("staging", "shared"))
+	// only the environment and its separator are a prefix, not any table starting with the same letters
+	require.Equal(t, "devices", ui.tableIn("staging", "devices"))
+}
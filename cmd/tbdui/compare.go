@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// compareUsage is the title of the compare prompt.
+const compareUsage = "Compare - <integration id> <environment> [region], against the same table in the current environment"
+
+// diffColors maps each kind of change to the color it is drawn in.
+var diffColors = map[dynamodb.Change]string{
+	dynamodb.Added:   "green",
+	dynamodb.Removed: "red",
+	dynamodb.Changed: "yellow",
+}
+
+// diffMarkers maps each kind of change to the marker drawn before it.
+var diffMarkers = map[dynamodb.Change]string{
+	dynamodb.Added:   "+",
+	dynamodb.Removed: "-",
+	dynamodb.Changed: "~",
+}
+
+// compare fetches an integration from the target table and the same table in another environment, and describes
+// the differences from the current environment to the other.  Input is "<integration id> <environment> [region]".
+func (ui TUI) compare(ctx context.Context, input string) (string, error) {
+	fields := strings.Fields(input)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("expected <integration id> <environment> [region], got %q", input)
+	}
+	id := fields[0]
+	environment, region, err := parseSwitch(strings.Join(fields[1:], " "), ui.region)
+	if err != nil {
+		return "", err
+	}
+
+	other, err := ui.connect(environment, region)
+	if err != nil {
+		return "", err
+	}
+	other.db.SetTable(ui.tableIn(other.environment, ui.db.Table()))
+
+	ctx = fresh(ctx)
+	here, err := fetchIntegration(ctx, ui.db, id)
+	if err != nil {
+		return "", err
+	}
+	there, err := fetchIntegration(ctx, other.db, id)
+	if err != nil {
+		return "", err
+	}
+	if here == nil && there == nil {
+		return "", fmt.Errorf("integration %q is in neither %s nor %s: %w", id, ui.db.Table(), other.db.Table(), dynamodb.ErrNotFound)
+	}
+
+	text := fmt.Sprintf("integration %s: %s %s -> %s %s\n", id, ui.location(), ui.db.Table(), location(other.environment, other.region), other.db.Table())
+	switch {
+	case here == nil:
+		text += fmt.Sprintf("only in %s\n", other.environment)
+	case there == nil:
+		text += fmt.Sprintf("only in %s\n", ui.environment)
+	}
+	return text + describeDiff(dynamodb.DiffItems(here, there)), nil
+}
+
+// fetchIntegration returns the raw item of an integration in the target table, or nil if there is no such integration.
+func fetchIntegration(ctx context.Context, db dynamodb.Backend, id string) (dynamodb.Item, error) {
+	i, err := db.Integration(ctx, id)
+	if errors.Is(err, dynamodb.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return i.Item()
+}
+
+// describeDiff summarizes the differences and lists one colored line for each.
+func describeDiff(diffs []dynamodb.AttributeDiff) string {
+	if len(diffs) == 0 {
+		return "no differences"
+	}
+
+	counts := make(map[dynamodb.Change]int)
+	var lines []string
+	for _, d := range diffs {
+		counts[d.Change]++
+		line := fmt.Sprintf("[%s %s](fg:%s): ", diffMarkers[d.Change], d.Name, diffColors[d.Change])
+		switch d.Change {
+		case dynamodb.Added:
+			line += attrText(d.New)
+		case dynamodb.Removed:
+			line += attrText(d.Old)
+		case dynamodb.Changed:
+			line += attrText(d.Old) + " -> " + attrText(d.New)
+		}
+		lines = append(lines, line)
+	}
+	summary := fmt.Sprintf("%d changed, %d added, %d removed", counts[dynamodb.Changed], counts[dynamodb.Added], counts[dynamodb.Removed])
+	return summary + "\n" + strings.Join(lines, "\n")
+}
+
+// attrText returns the plain JSON text of a single attribute.
+func attrText(av *ddb.AttributeValue) string {
+	b, err := json.Marshal(dynamodb.Item{"v": av}.JSON()["v"])
+	if err != nil {
+		return "?"
+	}
+	return string(b)
+}
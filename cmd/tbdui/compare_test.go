@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+func TestDescribeDiff(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "no differences", describeDiff(nil))
+
+	a, err := dynamodb.DecodeItem([]byte(`{"id": "a", "name": "Slack", "enabled": true, "old": 1}`))
+	require.NoError(t, err)
+	b, err := dynamodb.DecodeItem([]byte(`{"id": "a", "name": "Slack Pages", "enabled": true, "config": {"channel": "#alerts"}}`))
+	require.NoError(t, err)
+	require.Equal(t, `1 changed, 1 added, 1 removed
+[+ config](fg:green): {"channel":"#alerts"}
+[~ name](fg:yellow): "Slack" -> "Slack Pages"
+[- old](fg:red): 1`, describeDiff(dynamodb.DiffItems(a, b)))
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/swtch1/tbdui/conf"
+	"github.com/swtch1/tbdui/dynamodb"
+	"github.com/swtch1/tbdui/logger"
+)
+
+// switchUsage is the title of the environment switcher prompt.
+const switchUsage = "Switch - <environment> [region], the current region is kept when none is given"
+
+// localRegion is used against a custom endpoint when no region is given or configured, since local stand-ins accept
+// any region.
+const localRegion = "us-east-1"
+
+// connection is an open backend along with the environment and region it points at.
+type connection struct {
+	db          dynamodb.Backend
+	environment string
+	region      string
+}
+
+// connector opens a connection to an environment in a region.  An empty region falls back to the AWS config.
+type connector func(environment, region string) (connection, error)
+
+// newConnector sets up connections to DynamoDB.  When TBDUI_FIXTURES is set an in-memory backend is loaded from the
+// fixtures file at that path and AWS is never contacted, so nothing is recorded in telemetry.  Every environment is
+// then a view of the same fixture tables, so writes are kept across switches.  Connections are wrapped in a cache
+// unless the configured TTL is zero.
+func newConnector(l *logger.UILogger, c conf.Config, tokenProvider func() (string, error), telemetry *dynamodb.Telemetry) (connector, error) {
+	cached := func(db dynamodb.Backend) dynamodb.Backend {
+		if c.CacheTTL <= 0 {
+			return db
+		}
+		return dynamodb.NewCache(db, time.Duration(c.CacheTTL))
+	}
+	if path, ok := os.LookupEnv("TBDUI_FIXTURES"); ok {
+		db, err := dynamodb.NewMemoryDBFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+		db.SetLogger(l)
+		return func(environment, region string) (connection, error) {
+			view := db
+			if environment != "" && environment != db.Environment {
+				view = db.InEnvironment(environment)
+			}
+			return connection{db: cached(view), environment: view.Environment, region: region}, nil
+		}, nil
+	}
+
+	return func(environment, region string) (connection, error) {
+		db, err := newDynamoDB(l, c, tokenProvider, telemetry, environment, region)
+		if err != nil {
+			return connection{}, err
+		}
+		return connection{db: cached(db), environment: db.Environment, region: db.Region}, nil
+	}, nil
+}
+
+// newDynamoDB connects to DynamoDB for an environment.  Credentials come from the standard AWS chain, optionally
+// through a named profile and a role assumed for the environment.
+func newDynamoDB(l *logger.UILogger, c conf.Config, tokenProvider func() (string, error), telemetry *dynamodb.Telemetry, environment, region string) (*dynamodb.DB, error) {
+	if environment == "" {
+		return nil, fmt.Errorf("ENVIRONMENT env var is required")
+	}
+	env := c.Environment(environment)
+
+	dc := dynamodb.Config{
+		Profile:       env.Profile,
+		RoleARN:       env.RoleARN,
+		MFASerial:     env.MFASerial,
+		TokenProvider: tokenProvider,
+		Region:        region,
+		Environment:   environment,
+		Endpoint:      c.Endpoint,
+		STSEndpoint:   c.STSEndpoint,
+		Telemetry:     telemetry,
+	}
+	// a custom endpoint, like DynamoDB Local, doesn't need real credentials or a real region
+	if c.Endpoint != "" {
+		if _, ok := os.LookupEnv("AWS_ACCESS_KEY_ID"); !ok && env.Profile == "" && os.Getenv("AWS_PROFILE") == "" {
+			dc.Credentials = credentials.NewStaticCredentials("local", "local", "")
+		}
+		dc.DefaultRegion = localRegion
+	}
+
+	db, err := dynamodb.NewDB(dc)
+	if err != nil {
+		return nil, err
+	}
+	db.SetLogger(l)
+	return db, nil
+}
+
+// parseSwitch reads "<environment> [region]" from the switcher, keeping the current region when none is given.
+func parseSwitch(input, currentRegion string) (environment, region string, err error) {
+	fields := strings.Fields(input)
+	switch len(fields) {
+	case 1:
+		return fields[0], currentRegion, nil
+	case 2:
+		return fields[0], fields[1], nil
+	default:
+		return "", "", fmt.Errorf("expected <environment> [region], got %q", input)
+	}
+}
+
+// current returns the connection the TUI is using.
+func (ui TUI) current() connection {
+	return connection{db: ui.db, environment: ui.environment, region: ui.region}
+}
+
+// location describes where the TUI is connected.
+func (ui TUI) location() string {
+	return location(ui.environment, ui.region)
+}
+
+// location describes an environment and region.
+func location(environment, region string) string {
+	if region == "" {
+		return environment
+	}
+	return environment + " (" + region + ")"
+}
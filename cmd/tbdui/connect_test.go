@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/char"
+	"github.com/swtch1/tbdui/conf"
+	"github.com/swtch1/tbdui/logger"
+)
+
+func TestParseSwitch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                string
+		input               string
+		expectedEnvironment string
+		expectedRegion      string
+		expectErr           bool
+	}{
+		{
+			name:                "environment keeps the current region",
+			input:               "staging",
+			expectedEnvironment: "staging",
+			expectedRegion:      "us-east-1",
+		},
+		{
+			name:                "environment and region",
+			input:               "  prod   eu-west-1 ",
+			expectedEnvironment: "prod",
+			expectedRegion:      "eu-west-1",
+		},
+		{
+			name:      "nothing",
+			input:     " ",
+			expectErr: true,
+		},
+		{
+			name:      "too much",
+			input:     "prod eu-west-1 extra",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			environment, region, err := parseSwitch(tt.input, "us-east-1")
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedEnvironment, environment)
+			require.Equal(t, tt.expectedRegion, region)
+		})
+	}
+}
+
+func TestLocation(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "dev", location("dev", ""))
+	require.Equal(t, "dev (us-west-2)", location("dev", "us-west-2"))
+}
+
+func TestNewDynamoDBAssumesEnvironmentRole(t *testing.T) {
+	t.Parallel()
+
+	// a stand in for both STS and DynamoDB, which records every role assumed
+	var lock sync.Mutex
+	var assumed []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") == "" {
+			r.ParseForm()
+			lock.Lock()
+			assumed = append(assumed, r.PostForm)
+			lock.Unlock()
+			w.Header().Set("Content-Type", "text/xml")
+			w.Write([]byte(`<AssumeRoleResponse><AssumeRoleResult><Credentials>
+				<AccessKeyId>ASSUMED</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>token</SessionToken>
+				<Expiration>2100-01-01T00:00:00Z</Expiration>
+			</Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{"TableNames": ["dev-integrations", "prod-integrations"]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := conf.NewDefault()
+	c.Endpoint = srv.URL
+	c.STSEndpoint = srv.URL
+	c.Environments = map[string]conf.Environment{
+		"prod": {RoleARN: "arn:aws:iam::123456789012:role/prod", MFASerial: "arn:aws:iam::123456789012:mfa/someone"},
+	}
+
+	// the token is typed into the prompt shown while the first call runs
+	o, input := newTestOperation()
+	shown := make(chan struct{}, 1)
+	o.render = func() {
+		if o.mfa.Visible() {
+			select {
+			case shown <- struct{}{}:
+			default:
+			}
+		}
+	}
+	go func() {
+		<-shown
+		for _, k := range []string{"6", "5", "4", "3", "2", "1", char.ENTER} {
+			input <- k
+		}
+	}()
+
+	prod, err := newDynamoDB(logger.NewUILogger(), c, mfaPrompt{requests: o.tokens}.Token, nil, "prod", "us-east-1")
+	require.NoError(t, err)
+	var tables []string
+	err = o.run(func(ctx context.Context) (err error) {
+		tables, err = prod.Tables(ctx)
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"prod-integrations"}, tables)
+
+	// environments without a role use the base credentials
+	dev, err := newDynamoDB(logger.NewUILogger(), c, mfaPrompt{requests: o.tokens}.Token, nil, "dev", "us-east-1")
+	require.NoError(t, err)
+	tables, err = dev.Tables(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev-integrations"}, tables)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, assumed, 1)
+	require.Equal(t, "arn:aws:iam::123456789012:role/prod", assumed[0].Get("RoleArn"))
+	require.Equal(t, "arn:aws:iam::123456789012:mfa/someone", assumed[0].Get("SerialNumber"))
+	require.Equal(t, "654321", assumed[0].Get("TokenCode"))
+}
+
+// setenv sets an environment variable until the test ends, unsetting it when value is empty.  Tests which change the
+// environment can't run in parallel.
+func setenv(t *testing.T, name, value string) {
+	t.Helper()
+	old, ok := os.LookupEnv(name)
+	if value == "" {
+		require.NoError(t, os.Unsetenv(name))
+	} else {
+		require.NoError(t, os.Setenv(name, value))
+	}
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(name, old)
+		} else {
+			os.Unsetenv(name)
+		}
+	})
+}
+
+func TestNewDynamoDBEndpoint(t *testing.T) {
+	// a profile with its own credentials and region, in AWS config files the test points at
+	dir, err := ioutil.TempDir("", "tbdui-aws")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	credentialsFile := filepath.Join(dir, "credentials")
+	require.NoError(t, ioutil.WriteFile(credentialsFile, []byte("[work]\naws_access_key_id = WORK\naws_secret_access_key = secret\n"), 0600))
+	configFile := filepath.Join(dir, "config")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte("[profile work]\nregion = eu-west-1\n"), 0600))
+
+	tests := []struct {
+		name      string
+		profile   string
+		accessKey string
+		region    string
+		// expectedKey is the access key requests are signed with
+		expectedKey    string
+		expectedRegion string
+	}{
+		{
+			name:           "no credentials or region fall back to local ones",
+			expectedKey:    "local",
+			expectedRegion: localRegion,
+		},
+		{
+			name:           "region given",
+			region:         "us-west-2",
+			expectedKey:    "local",
+			expectedRegion: "us-west-2",
+		},
+		{
+			name:           "credentials from the environment",
+			accessKey:      "ENV",
+			expectedKey:    "ENV",
+			expectedRegion: localRegion,
+		},
+		{
+			name:           "credentials and region from a profile",
+			profile:        "work",
+			expectedKey:    "WORK",
+			expectedRegion: "eu-west-1",
+		},
+	}
+
+	signature := regexp.MustCompile(`Credential=([^/]+)/[^/]+/([^/]+)/`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_PROFILE", "AWS_DEFAULT_PROFILE", "AWS_REGION", "AWS_DEFAULT_REGION"} {
+				setenv(t, name, "")
+			}
+			setenv(t, "AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+			setenv(t, "AWS_CONFIG_FILE", configFile)
+			setenv(t, "AWS_ACCESS_KEY_ID", tt.accessKey)
+			if tt.accessKey != "" {
+				setenv(t, "AWS_SECRET_ACCESS_KEY", "secret")
+			}
+
+			// a stand in for DynamoDB, which records the key and region of every request it is sent
+			var lock sync.Mutex
+			var signed [][]string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				lock.Lock()
+				signed = append(signed, signature.FindStringSubmatch(r.Header.Get("Authorization")))
+				lock.Unlock()
+				w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+				w.Write([]byte(`{"TableNames": ["dev-integrations"]}`))
+			}))
+			t.Cleanup(srv.Close)
+
+			c := conf.NewDefault()
+			c.Endpoint = srv.URL
+			c.Profile = tt.profile
+			db, err := newDynamoDB(logger.NewUILogger(), c, nil, nil, "dev", tt.region)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedRegion, db.Region)
+
+			tables, err := db.Tables(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, []string{"dev-integrations"}, tables)
+
+			lock.Lock()
+			defer lock.Unlock()
+			require.Len(t, signed, 1, "requests go to the configured endpoint")
+			require.Len(t, signed[0], 3)
+			require.Equal(t, tt.expectedKey, signed[0][1])
+			require.Equal(t, tt.expectedRegion, signed[0][2])
+		})
+	}
+}
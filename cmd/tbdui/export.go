@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// exportUsage describes the input to the export prompt.
+const exportUsage = "Export - <format: jsonl, csv, ddb> <path> [scan], scan exports the whole table instead of the results"
+
+// export writes items to a local file.  Input is "<format> <path> [scan]".  The current results are written unless
+// scan is given, in which case every item in table is, scanned by segments in parallel.  A summary of the export is
+// returned.
+func (ui TUI) export(ctx context.Context, input string, results []dynamodb.Item, table string, segments int) (string, error) {
+	fields := strings.Fields(input)
+	if len(fields) < 2 || len(fields) > 3 || (len(fields) == 3 && fields[2] != "scan") {
+		return "", fmt.Errorf("expected <format> <path> [scan], got %q", input)
+	}
+	format, err := dynamodb.ParseFormat(fields[0])
+	if err != nil {
+		return "", err
+	}
+	path := fields[1]
+
+	items := results
+	if len(fields) == 3 {
+		if items, err = ui.db.ParallelScan(fresh(ctx), table, segments, nil); err != nil {
+			return "", err
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create export file: %w", err)
+	}
+	if err := dynamodb.WriteItems(f, format, items); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	ui.Log("exported %d items to %s", len(items), path)
+	return fmt.Sprintf("exported %d items to %s as %s", len(items), path, format), nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/dynamodb"
+	"github.com/swtch1/tbdui/logger"
+)
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "tbdui-export")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	db, err := dynamodb.NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	ui := TUI{db: db, logger: logger.NewUILogger()}
+	results, err := dynamodb.DecodeItem([]byte(`{"integration_id": "int-slack-001", "name": "Slack Alerts"}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		input         string
+		expectedLines int
+		expectErr     bool
+	}{
+		{
+			name:          "results",
+			input:         "jsonl results.jsonl",
+			expectedLines: 1,
+		},
+		{
+			name:          "whole table",
+			input:         "  ddb   table.json  scan ",
+			expectedLines: 4,
+		},
+		{
+			name:      "no path",
+			input:     "jsonl",
+			expectErr: true,
+		},
+		{
+			name:      "not scan",
+			input:     "jsonl out.jsonl everything",
+			expectErr: true,
+		},
+		{
+			name:      "too much",
+			input:     "jsonl out.jsonl scan now",
+			expectErr: true,
+		},
+		{
+			name:      "unknown format",
+			input:     "xml out.xml",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := strings.Fields(tt.input)
+			if len(fields) > 1 {
+				fields[1] = filepath.Join(dir, fields[1])
+			}
+			summary, err := ui.export(context.Background(), strings.Join(fields, " "), []dynamodb.Item{results}, "dev-integrations", 2)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, summary, fields[1])
+
+			b, err := ioutil.ReadFile(fields[1])
+			require.NoError(t, err)
+			require.Len(t, strings.Split(strings.TrimSpace(string(b)), "\n"), tt.expectedLines)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+const (
+	// importUsage describes the input to the import prompt.
+	importUsage = "Import - <path> to a JSON Lines or DynamoDB JSON file"
+	// maxPlannedKeys is the most keys of each kind of change listed in an import dry-run.
+	maxPlannedKeys = 5
+)
+
+// planImport reads records from the file at path and compares them with the items stored in table.
+func (ui TUI) planImport(ctx context.Context, path, table string) (dynamodb.TableSchema, dynamodb.ImportPlan, error) {
+	schema, err := ui.db.Schema(ctx, table)
+	if err != nil {
+		return schema, dynamodb.ImportPlan{}, err
+	}
+
+	f, err := os.Open(strings.TrimSpace(path))
+	if err != nil {
+		return schema, dynamodb.ImportPlan{}, fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer f.Close()
+	records, err := dynamodb.ReadItems(f)
+	if err != nil {
+		return schema, dynamodb.ImportPlan{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	keys := make([]dynamodb.Item, 0, len(records))
+	for _, r := range records {
+		keys = append(keys, schema.KeyOf(r))
+	}
+	current, err := ui.db.GetItems(ctx, schema, keys)
+	if err != nil {
+		return schema, dynamodb.ImportPlan{}, err
+	}
+	plan, err := dynamodb.PlanImport(schema.KeySchema, records, current)
+	return schema, plan, err
+}
+
+// describePlan formats an import dry-run, listing the keys of the first few inserts and updates.
+func describePlan(schema dynamodb.TableSchema, plan dynamodb.ImportPlan) string {
+	text := fmt.Sprintf("Import into %s: %s\n", schema.Name, plan.Summary())
+	for _, change := range []struct {
+		name  string
+		items []dynamodb.Item
+	}{
+		{"insert", plan.Inserts},
+		{"update", plan.Updates},
+	} {
+		for i, item := range change.items {
+			if i == maxPlannedKeys {
+				text += fmt.Sprintf("  ...and %d more %ss\n", len(change.items)-maxPlannedKeys, change.name)
+				break
+			}
+			b, err := json.Marshal(schema.KeyOf(item))
+			if err != nil {
+				return err.Error()
+			}
+			text += fmt.Sprintf("  %s %s\n", change.name, b)
+		}
+	}
+	return text
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
-	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/gizak/termui/v3"
 	"github.com/sirupsen/logrus"
 	"github.com/swtch1/tbdui/char"
@@ -27,9 +31,48 @@ const (
 )
 
 func main() {
+	configPath := flag.String("config", defaultConfigPath(), "path to the JSON configuration file")
+	endpoint := flag.String("endpoint", "", "DynamoDB endpoint URL, e.g. http://localhost:8000 for DynamoDB Local")
+	profile := flag.String("profile", "", "named AWS profile to use for credentials")
+	scanWorkers := flag.Int("scan-workers", 0, "number of segments to scan tables with in parallel")
+	cacheTTL := flag.Duration("cache-ttl", 0, "how long read results are cached, 0 disables the cache")
+	flag.Parse()
+
+	cfg, err := conf.Load(*configPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	// the flag takes precedence over the environment, which takes precedence over the config file
+	if e, ok := os.LookupEnv("AWS_ENDPOINT_URL_DYNAMODB"); ok {
+		cfg.Endpoint = e
+	}
+	if *endpoint != "" {
+		cfg.Endpoint = *endpoint
+	}
+	if *profile != "" {
+		cfg.Profile = *profile
+	}
+	if *scanWorkers > 0 {
+		cfg.ScanWorkers = *scanWorkers
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "cache-ttl" {
+			cfg.CacheTTL = conf.Duration(*cacheTTL)
+		}
+	})
+
 	log := logger.NewUILogger()
+	input := make(chan string)
+	tokens := make(chan chan tokenReply)
 
-	dynDB, err := newDynamoDB(log)
+	telemetry := dynamodb.NewTelemetry()
+	connect, err := newConnector(log, cfg, mfaPrompt{requests: tokens}.Token, telemetry)
+	if err != nil {
+		fmt.Println("failed to setup connection to DynamoDB:", err)
+		os.Exit(1)
+	}
+	conn, err := connect(os.Getenv("ENVIRONMENT"), os.Getenv("AWS_REGION"))
 	if err != nil {
 		fmt.Println("failed to setup connection to DynamoDB:", err)
 		os.Exit(1)
@@ -40,13 +83,12 @@ func main() {
 	}
 	defer termui.Close()
 
-	input := make(chan string)
-	tui := newTUI(dynDB, input, log)
+	tui := newTUI(conn, connect, telemetry, input, tokens, log)
 
 	errCh := make(chan error)
 	go func() {
 		// if the tui errors signal for app termination
-		if err := tui.Run(conf.NewDefault()); err != nil {
+		if err := tui.Run(cfg); err != nil {
 			errCh <- err
 		}
 	}()
@@ -67,48 +109,48 @@ func main() {
 	}
 }
 
-func newDynamoDB(l *logger.UILogger) (*dynamodb.DB, error) {
-	accessKeyID, ok := os.LookupEnv("AWS_ACCESS_KEY_ID")
-	if !ok {
-		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID env var is required")
-	}
-	secretAccessKey, ok := os.LookupEnv("AWS_SECRET_ACCESS_KEY")
-	if !ok {
-		return nil, fmt.Errorf("AWS_SECRET_ACCESS_KEY env var is required")
+// defaultConfigPath is the config file used when the -config flag is not given.
+func defaultConfigPath() string {
+	if path, ok := os.LookupEnv("TBDUI_CONFIG"); ok {
+		return path
 	}
-	region, ok := os.LookupEnv("AWS_REGION")
-	if !ok {
-		return nil, fmt.Errorf("AWS_REGION env var is required")
-	}
-	environment, ok := os.LookupEnv("ENVIRONMENT")
-	if !ok {
-		return nil, fmt.Errorf("ENVIRONMENT env var is required")
-	}
-
-	db, err := dynamodb.NewDB(credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""), region, environment)
+	home, err := os.UserHomeDir()
 	if err != nil {
-		return nil, err
+		return ".tbdui.json"
 	}
-	db.SetLogger(l)
-	return db, nil
+	return filepath.Join(home, ".tbdui.json")
 }
 
 // TUI is a terminal user interface.
 type TUI struct {
-	db      *dynamodb.DB
+	db          dynamodb.Backend
+	environment string
+	region      string
+	// connect opens the backend for another environment or region.
+	connect connector
+	// telemetry records the cost of requests over the whole session, across connections.
+	telemetry *dynamodb.Telemetry
+	// inputCh delivers key presses.  Run returns once it is closed.
 	inputCh chan string
-	logger  *logger.UILogger
+	// tokens are requests for MFA token codes, answered by the running operation or the TUI loop
+	tokens chan chan tokenReply
+	logger *logger.UILogger
+	// render draws widgets to the terminal, and dimensions returns its width and height.  They are termui's unless
+	// the screen is faked, e.g. in tests.
+	render     func(...termui.Drawable)
+	dimensions func() (int, int)
 }
 
 // Run starts a continuous loop that will draw the screen
 func (ui TUI) Run(c conf.Config) error {
-	termWidth, termHeight := termui.TerminalDimensions()
+	termWidth, termHeight := ui.dimensions()
 
 	borderWidth := 1
 	startHeight := 0
 	leftBorder := borderWidth
 	rightBorder := termWidth - borderWidth
 	inputBoxHeight := 3
+	telemetryHeight := 5
 	inputBoxWidth := termWidth / 3
 
 	// text across the top
@@ -137,16 +179,43 @@ func (ui TUI) Run(c conf.Config) error {
 		Y2: start + inputBoxHeight,
 	})
 
-	// table filter box on the left
+	// partition key box on the left, filling it switches searches to a key query
 	start = 9
-	tableFilterBox := component.NewInputBox("Filter Table", ":type partial table name to filter", c, component.Dimensions{
+	partitionKeyBox := component.NewInputBox("Partition Key", ":type partition key value to query", c, component.Dimensions{
 		X1: leftBorder,
 		Y1: start,
 		X2: inputBoxWidth,
 		Y2: start + inputBoxHeight,
 	})
 
+	// sort key condition box on the left
 	start = 12
+	sortKeyBox := component.NewInputBox("Sort Key", ":=, begins_with, between, <, <=, >, >=", c, component.Dimensions{
+		X1: leftBorder,
+		Y1: start,
+		X2: inputBoxWidth,
+		Y2: start + inputBoxHeight,
+	})
+
+	// index list on the left, choosing an index makes it the target of key queries
+	start = 15
+	indexList := component.NewList("Query Index", c, component.Dimensions{
+		X1: leftBorder,
+		Y1: start,
+		X2: inputBoxWidth,
+		Y2: start + 5,
+	})
+
+	// table filter box on the left
+	start = 20
+	tableFilterBox := component.NewInputBox("Filter Table", ":type partial table name to filter", c, component.Dimensions{
+		X1: leftBorder,
+		Y1: start,
+		X2: inputBoxWidth,
+		Y2: start + inputBoxHeight,
+	})
+
+	start = 23
 	tableList := component.NewList("Select Table", c, component.Dimensions{
 		X1: leftBorder,
 		Y1: start,
@@ -159,66 +228,925 @@ func (ui TUI) Run(c conf.Config) error {
 		X1: leftBorder + inputBoxWidth,
 		Y1: searchBox.Dimensions().Y1,
 		X2: rightBorder,
-		Y2: termHeight - borderWidth,
+		Y2: termHeight - borderWidth - inputBoxHeight - telemetryHeight,
 	})
 	outputBox.HideUnselectedText = false
+	// the output box is only written to while editing an item
+	outputBox.AllowWrite = false
+	// the items shown in the output box, and the original of the item being edited, if any
+	var view resultView
+	var editing dynamodb.Item
+
+	// request costs, under the output box
+	telemetryPanel := component.NewTelemetry("Capacity", c, component.Dimensions{
+		X1: leftBorder + inputBoxWidth,
+		Y1: termHeight - borderWidth - inputBoxHeight - telemetryHeight,
+		X2: rightBorder,
+		Y2: termHeight - borderWidth - inputBoxHeight,
+	})
+
+	// PartiQL console, under the telemetry panel
+	consoleBox := component.NewInputBox("PartiQL", ":type a statement, <Enter> to run, <Ctrl + n> for the next page", c, component.Dimensions{
+		X1: leftBorder + inputBoxWidth,
+		Y1: termHeight - borderWidth - inputBoxHeight,
+		X2: rightBorder,
+		Y2: termHeight - borderWidth,
+	})
+	// the statement being paged through in the console, and the token for its next page
+	var statement, nextToken string
+	var page int
+	// rerun repeats the search or scan which filled the results, optionally skipping the cache
+	var rerun func(refresh bool)
+	// the stream being tailed into the output box, if any
+	var tail *streamTail
+	// tailHeight and tailWidth are the lines of the tail which fit in the output box
+	tailHeight := outputBox.Dimensions().Y2 - outputBox.Dimensions().Y1 - borderWidth*2
+	tailWidth := outputBox.Dimensions().X2 - outputBox.Dimensions().X1 - borderWidth*2
+	// the last table profile, which can be sorted by each of its columns
+	var profile *dynamodb.TableProfile
+
+	// metadata of the table highlighted in the table list, over the right of the output box while the list is selected
+	tablePanel := component.NewModal("Table", c, component.Dimensions{
+		X1: rightBorder - termWidth/3,
+		Y1: outputBox.Dimensions().Y1,
+		X2: rightBorder,
+		Y2: outputBox.Dimensions().Y2,
+	})
+	describer := newTableDescriber()
+	// the table shown in the table panel
+	var described string
+
+	// confirmation dialog, over everything else, and the action to take when it is confirmed
+	confirmModal := component.NewModal("Confirm", c, component.Dimensions{
+		X1: termWidth / 4,
+		Y1: termHeight / 3,
+		X2: termWidth * 3 / 4,
+		Y2: termHeight * 2 / 3,
+	})
+	var confirm func()
+
+	// single line input, over everything else, and the action to take with the input when it is submitted
+	prompt := component.NewPrompt(c, component.Dimensions{
+		X1: termWidth / 6,
+		Y1: termHeight / 3,
+		X2: termWidth * 5 / 6,
+		Y2: termHeight/3 + inputBoxHeight,
+	})
+	var submit func(string)
+
+	// masked prompt for MFA token codes, over everything else, shown when credentials need one
+	tokenPrompt := component.NewPrompt(c, component.Dimensions{
+		X1: termWidth / 6,
+		Y1: termHeight / 3,
+		X2: termWidth * 5 / 6,
+		Y2: termHeight/3 + inputBoxHeight,
+	})
+	tokenPrompt.Mask = true
+
+	// items deleted this session, so they can be written back
+	var undo undoBuffer
+
+	// while staging, edits, deletes and clones are held back to be committed together in one transaction
+	var staged stagedChanges
+	var staging bool
+
+	// the key schema of the target table names the key boxes and builds queries, against the table itself unless
+	// an index is chosen
+	var index string
+	var schema dynamodb.TableSchema
+	// the attributes searches and scans fetch, or everything when empty
+	var attributes dynamodb.Projection
 
 	// set all types to be rendered here so we can switch things on and off
-	mr := NewMassRenderer([]Renderable{
+	mr := NewMassRenderer(ui.render, []Renderable{
 		topText,
 		searchBox,
 		companyFilterBox,
+		partitionKeyBox,
+		sortKeyBox,
+		indexList,
 		tableFilterBox,
 		tableList,
 		outputBox,
+		telemetryPanel,
+		consoleBox,
+		tablePanel,
+		confirmModal,
+		prompt,
+		tokenPrompt,
 	})
 
+	// header describes where searches go, and any changes being staged
+	header := func() string {
+		h := ui.header(index)
+		if len(attributes) > 0 {
+			h += " | attributes: " + attributes.String()
+		}
+		if staging {
+			h += fmt.Sprintf(" | staging %d changes, <Ctrl + x> to commit", staged.Len())
+		}
+		return h
+	}
+
+	// full table scans are split into this many segments, scanned in parallel
+	scanWorkers := c.ScanWorkers
+
+	// backend calls run as cancellable operations, which own the input until they finish
+	op := operation{
+		inputCh: ui.inputCh,
+		tokens:  ui.tokens,
+		status:  topText,
+		header:  header,
+		mfa:     tokenPrompt,
+		render:  mr.Render,
+	}
+
+	// load fills the table list with every table in the environment and targets the default table.  Typed inputs,
+	// including the table filter, are left alone.
+	load := func() {
+		tableList.Flush()
+		index = ""
+		var tables []string
+		var ttl dynamodb.TimeToLive
+		var ttlErr error
+		err := op.run(func(ctx context.Context) (err error) {
+			if tables, err = ui.db.Tables(ctx); err != nil {
+				return err
+			}
+			if schema, err = ui.db.Schema(ctx, ui.db.Table()); err != nil {
+				return err
+			}
+			ttl, ttlErr = ui.db.TimeToLive(ctx, schema.Name)
+			return nil
+		})
+		if err != nil {
+			outputBox.Overwrite(err.Error())
+		}
+		// items are still shown without their expiry when the TTL can't be read
+		if ttlErr != nil {
+			ui.Log("%v", ttlErr)
+		}
+		view.SetTTL(schema.Name, ttl)
+		for _, t := range tables {
+			tableList.AddRow(t)
+		}
+		fillIndexList(schema, indexList)
+		setKeyTitles(schema.KeySchema, partitionKeyBox, sortKeyBox)
+		topText.Overwrite(header())
+	}
+	load()
+
 	// do you want tabs? because this is how you get tabs!
-	tabOrder := []Selectable{searchBox, companyFilterBox, tableFilterBox, tableList, outputBox}
+	tabOrder := []Selectable{searchBox, companyFilterBox, partitionKeyBox, sortKeyBox, indexList, tableFilterBox, tableList, outputBox, consoleBox}
 	sh := NewSelectionHandler(tabOrder, ui.logger)
 
 	var selected Writer = sh.Next()
 	for {
+		// the table panel follows the highlight while the table list is selected
+		if t := tableList.Selected(); selected == tableList && t != "" {
+			if t != described {
+				described = t
+				tablePanel.Show("describing " + t + "...")
+				describer.Describe(ui.db, t)
+			}
+		} else if described != "" {
+			described = ""
+			describer.Stop()
+			tablePanel.Hide()
+		}
+
+		telemetryPanel.Set(describeTelemetry(ui.telemetry.Snapshot()))
 		mr.Render()
+
+		// nil channels never receive, so the tail is only read while there is one
+		var tailRecords <-chan dynamodb.StreamRecord
+		var tailDone <-chan error
+		if tail != nil {
+			tailRecords, tailDone = tail.records, tail.done
+		}
+
 		select {
-		case c := <-ui.inputCh:
+		case r := <-tailRecords:
+			// the output box is left alone while an item is edited in it
+			if tail.Add(r, searchBox.Contents(), companyFilterBox.Contents(), tailWidth) && editing == nil {
+				outputBox.Overwrite(tail.String(tailHeight))
+			}
+
+		case d := <-describer.results:
+			// descriptions of tables no longer highlighted, or from before a switch, are dropped
+			if !describer.Latest(d) || d.table != described {
+				continue
+			}
+			if d.err != nil {
+				tablePanel.Show(d.err.Error())
+				continue
+			}
+			tablePanel.Show(describeTable(d.info))
+
+		case err := <-tailDone:
+			tail = nil
+			outputBox.SetTitle("")
+			outputBox.Overwrite(err.Error())
+
+		// background calls, like describing tables and tailing streams, can need MFA tokens too
+		case reply := <-ui.tokens:
+			op.answer(reply)
+
+		case c, ok := <-ui.inputCh:
+			if !ok {
+				return nil
+			}
 			// cheap debug logging
 			if debugLog {
 				ui.Log("received input: %v", c)
 			}
 
+			// the confirmation dialog takes all input while it is shown
+			if confirmModal.Visible() {
+				switch c {
+				case "y", "Y":
+					confirmModal.Hide()
+					confirm()
+				case "n", "N", char.ESCAPE:
+					confirmModal.Hide()
+				}
+				continue
+			}
+
+			// the prompt takes all input while it is shown
+			if prompt.Visible() {
+				switch c {
+				case char.ENTER:
+					prompt.Hide()
+					submit(prompt.Contents())
+				case char.ESCAPE:
+					prompt.Hide()
+				default:
+					prompt.Write(c)
+				}
+				continue
+			}
+
 			switch c {
 
 			// switch between elements
 			case char.TAB:
 				selected = sh.Next()
 
+			// browse the results
+			case char.PAGE_DOWN, char.PAGE_UP:
+				if editing != nil {
+					continue
+				}
+				if c == char.PAGE_DOWN {
+					view.Next()
+				} else {
+					view.Previous()
+				}
+				outputBox.Overwrite(view.String())
+
+			// edit the current item in the output box
+			case char.CTRL_E:
+				item, ok := view.Current()
+				if !ok || editing != nil {
+					continue
+				}
+				if view.table == "" {
+					outputBox.Overwrite("items from the PartiQL console can't be edited here, use an UPDATE statement")
+					continue
+				}
+				// projected and index items can be missing attributes, so the whole item is read to be edited
+				table, partial := view.table, view.Partial()
+				err := op.run(func(ctx context.Context) (err error) {
+					item, err = ui.wholeItem(ctx, table, item, partial)
+					return err
+				})
+				if err != nil {
+					outputBox.Overwrite(err.Error())
+					continue
+				}
+				view.Replace(item)
+				editing = item
+				outputBox.AllowWrite = true
+				outputBox.SetTitle("Editing - <Ctrl + s> to save, <Escape> to cancel")
+				outputBox.Overwrite(formatItem(item))
+				selected = sh.Select(outputBox)
+
+			// save the item being edited, as long as nobody else changed it first
+			case char.CTRL_S:
+				if editing == nil {
+					continue
+				}
+				table, original, edited := view.table, editing, outputBox.Contents()
+				if staging {
+					var change dynamodb.StagedChange
+					err := op.run(func(ctx context.Context) (err error) {
+						change, err = ui.stageEdit(ctx, table, original, edited)
+						return err
+					})
+					if err != nil {
+						outputBox.SetTitle(fmt.Sprintf("Editing - %v", err))
+						continue
+					}
+					staged.Add(change)
+					editing = nil
+					outputBox.AllowWrite = false
+					outputBox.SetTitle("")
+					view.note = stagedNote(change, staged.Len())
+					outputBox.Overwrite(view.String())
+					topText.Overwrite(header())
+					continue
+				}
+				var written dynamodb.Item
+				err := op.run(func(ctx context.Context) (err error) {
+					written, err = ui.save(ctx, table, original, edited)
+					return err
+				})
+				if errors.Is(err, dynamodb.ErrConflict) {
+					outputBox.SetTitle("Conflict - the item was changed by someone else since it was read, <Escape> to discard your edits")
+					continue
+				}
+				if err != nil {
+					outputBox.SetTitle(fmt.Sprintf("Editing - %v", err))
+					continue
+				}
+				ui.Log("saved item in %s", view.table)
+				view.Replace(written)
+				editing = nil
+				outputBox.AllowWrite = false
+				outputBox.SetTitle("")
+				outputBox.Overwrite(view.String())
+
+			// delete the current item, once confirmed
+			case char.CTRL_D:
+				item, ok := view.Current()
+				if !ok || editing != nil {
+					continue
+				}
+				if view.table == "" {
+					outputBox.Overwrite("items from the PartiQL console can't be deleted here, use a DELETE statement")
+					continue
+				}
+				table := view.table
+				var target dynamodb.TableSchema
+				err := op.run(func(ctx context.Context) (err error) {
+					target, err = ui.db.Schema(ctx, table)
+					return err
+				})
+				if err != nil {
+					outputBox.Overwrite(err.Error())
+					continue
+				}
+				if staging {
+					// the whole item is staged, since it goes in the undo buffer once the delete is committed
+					partial := view.Partial()
+					err := op.run(func(ctx context.Context) (err error) {
+						item, err = ui.wholeItem(ctx, table, item, partial)
+						return err
+					})
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						continue
+					}
+					change := dynamodb.StagedChange{Action: dynamodb.Delete, Schema: target, Item: item}
+					staged.Add(change)
+					view.note = stagedNote(change, staged.Len())
+					outputBox.Overwrite(view.String())
+					topText.Overwrite(header())
+					continue
+				}
+				confirmModal.Show(fmt.Sprintf("Delete this item from %s?\n\n%s\n\n<y> to delete, <n> to cancel", table, formatItem(target.KeyOf(item))))
+				confirm = func() {
+					var old dynamodb.Item
+					err := op.run(func(ctx context.Context) (err error) {
+						old, err = ui.db.DeleteItem(ctx, target, item)
+						return err
+					})
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					ui.Log("deleted item from %s", table)
+					undo.Push(ui.current(), table, old)
+					view.Remove()
+					view.note = fmt.Sprintf("deleted item from %s | <Ctrl + z> to undo", table)
+					outputBox.Overwrite(view.String())
+				}
+
+			// write the most recently deleted item back
+			case char.CTRL_Z:
+				d, ok := undo.Peek()
+				if !ok || editing != nil {
+					continue
+				}
+				// the item goes back where it was deleted from, which may not be where the TUI is connected now
+				where := location(d.conn.environment, d.conn.region)
+				err := op.run(func(ctx context.Context) error {
+					target, err := d.conn.db.Schema(ctx, d.table)
+					if err != nil {
+						return err
+					}
+					return d.conn.db.CreateItem(ctx, target, d.item)
+				})
+				if err != nil {
+					outputBox.Overwrite(fmt.Sprintf("failed to restore item in %s in %s: %v", d.table, where, err))
+					continue
+				}
+				ui.Log("restored item in %s in %s", d.table, where)
+				undo.Pop()
+				note := fmt.Sprintf("restored item in %s in %s", d.table, where)
+				if undo.Len() > 0 {
+					note += fmt.Sprintf(" | <Ctrl + z> to undo %d more", undo.Len())
+				}
+				if d.conn.environment != ui.environment || d.conn.region != ui.region {
+					view.note = note
+				} else if view.table == d.table {
+					view.Insert(d.item)
+					view.note = note
+				} else {
+					view.Set(d.table, []dynamodb.Item{d.item}, note)
+				}
+				outputBox.Overwrite(view.String())
+
+			// export results, or the whole table, to a file
+			case char.CTRL_O:
+				if editing != nil {
+					continue
+				}
+				prompt.Show(exportUsage, "jsonl export.jsonl")
+				submit = func(input string) {
+					table := view.table
+					if table == "" {
+						table = schema.Name
+					}
+					var summary string
+					err := op.run(func(ctx context.Context) (err error) {
+						summary, err = ui.export(ctx, input, view.items, table, scanWorkers)
+						return err
+					})
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					view.note = summary
+					outputBox.Overwrite(view.String())
+				}
+
+			// import records from a file into the target table, once the dry-run is confirmed
+			case char.CTRL_U:
+				if editing != nil {
+					continue
+				}
+				prompt.Show(importUsage, "")
+				submit = func(path string) {
+					var target dynamodb.TableSchema
+					var plan dynamodb.ImportPlan
+					err := op.run(func(ctx context.Context) (err error) {
+						target, plan, err = ui.planImport(ctx, path, schema.Name)
+						return err
+					})
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					changes := plan.Changes()
+					if len(changes) == 0 {
+						outputBox.Overwrite(fmt.Sprintf("nothing to import into %s: %s", target.Name, plan.Summary()))
+						return
+					}
+					confirmModal.Show(describePlan(target, plan) + "\n<y> to write the changes, <n> to cancel")
+					confirm = func() {
+						var wrote int
+						err := op.run(func(ctx context.Context) (err error) {
+							wrote, err = ui.db.PutItems(ctx, target.Name, changes)
+							return err
+						})
+						if err != nil {
+							outputBox.Overwrite(err.Error())
+							return
+						}
+						ui.Log("imported %d items into %s", wrote, target.Name)
+						view.Set(target.Name, changes, fmt.Sprintf("imported %d items into %s: %s", wrote, target.Name, plan.Summary()))
+						outputBox.Overwrite(view.String())
+					}
+				}
+
+			// switch to another environment or region
+			case char.CTRL_G:
+				if editing != nil {
+					continue
+				}
+				if staged.Len() > 0 {
+					outputBox.Overwrite(fmt.Sprintf("%d staged changes belong to %s, commit them with <Ctrl + x> or discard them with <Ctrl + w> before switching", staged.Len(), ui.location()))
+					continue
+				}
+				prompt.Show(switchUsage, ui.environment+" "+ui.region)
+				submit = func(input string) {
+					environment, region, err := parseSwitch(input, ui.region)
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					conn, err := ui.connect(environment, region)
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					if tail != nil {
+						tail.Stop()
+						tail = nil
+						outputBox.SetTitle("")
+					}
+					ui.db, ui.environment, ui.region = conn.db, conn.environment, conn.region
+					ui.Log("switched to %s", ui.location())
+
+					// results and pages belong to the old connection, deleted items are restored through it
+					statement, nextToken, page = "", "", 0
+					rerun = nil
+					profile = nil
+					view.Set("", nil, "")
+					// the table panel describes tables of the old connection
+					described = ""
+					describer.Stop()
+					tablePanel.Hide()
+					outputBox.Overwrite("switched to " + ui.location())
+					load()
+				}
+
+			// pick the attributes searches and scans fetch
+			case char.CTRL_Q:
+				if editing != nil {
+					continue
+				}
+				prompt.Show(projectionUsage, attributes.String())
+				submit = func(input string) {
+					p, err := dynamodb.ParseProjection(input)
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					attributes = p
+					ui.Log("%s", projectionNote(attributes))
+					outputBox.Overwrite(projectionNote(attributes) + " | <Enter> to search again, <Ctrl + t> to scan")
+					topText.Overwrite(header())
+				}
+
+			// compare an integration with another environment
+			case char.CTRL_K:
+				if editing != nil {
+					continue
+				}
+				prompt.Show(compareUsage, searchBox.Contents()+" ")
+				submit = func(input string) {
+					var text string
+					err := op.run(func(ctx context.Context) (err error) {
+						text, err = ui.compare(ctx, input)
+						return err
+					})
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					outputBox.Overwrite(text)
+				}
+
+			// clone the current item into another environment, or another company, once the preview is confirmed
+			case char.CTRL_P:
+				item, ok := view.Current()
+				if !ok || view.table == "" || editing != nil {
+					continue
+				}
+				// projected and index items can be missing attributes, so the whole item is read to be cloned
+				table, partial := view.table, view.Partial()
+				prompt.Show(cloneUsage, ui.environment+" ")
+				submit = func(input string) {
+					var plan clonePlan
+					err := op.run(func(ctx context.Context) error {
+						whole, err := ui.wholeItem(ctx, table, item, partial)
+						if err != nil {
+							return err
+						}
+						plan, err = ui.planClone(ctx, input, table, whole)
+						return err
+					})
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					if staging {
+						if plan.conn.environment != ui.environment || plan.conn.region != ui.region {
+							outputBox.Overwrite(fmt.Sprintf("staged changes are committed in %s, clone there or stop staging first", ui.location()))
+							return
+						}
+						change := dynamodb.StagedChange{Action: dynamodb.Put, Schema: plan.schema, Item: plan.item}
+						staged.Add(change)
+						outputBox.Overwrite(stagedNote(change, staged.Len()) + "\n" + formatItem(plan.item))
+						topText.Overwrite(header())
+						return
+					}
+					confirmModal.Show(plan.describe() + "\n<y> to write the clone, <n> to cancel")
+					confirm = func() {
+						err := op.run(func(ctx context.Context) error {
+							return plan.conn.db.CreateItem(ctx, plan.schema, plan.item)
+						})
+						if err != nil {
+							outputBox.Overwrite(err.Error())
+							return
+						}
+						ui.Log("cloned item into %s in %s", plan.schema.Name, plan.conn.environment)
+						outputBox.Overwrite(fmt.Sprintf("cloned into %s in %s\n%s", plan.schema.Name, location(plan.conn.environment, plan.conn.region), formatItem(plan.item)))
+					}
+				}
+
+			// scan the whole target table into the results
+			case char.CTRL_T:
+				if editing != nil {
+					continue
+				}
+				table, projection := schema.Name, projectionFor(attributes, schema, "")
+				rerun = func(refresh bool) {
+					var items []dynamodb.Item
+					var cache *dynamodb.CacheControl
+					err := op.run(func(ctx context.Context) (err error) {
+						ctx, cache = dynamodb.WithCacheControl(ctx, refresh)
+						items, err = ui.db.ParallelScan(ctx, table, scanWorkers, projection)
+						return err
+					})
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					note := fmt.Sprintf("scanned %d items from %s with %d workers", len(items), table, scanWorkers)
+					view.Set(table, items, withCacheNote(note, cache))
+					view.projection = projection
+					outputBox.Overwrite(view.String())
+				}
+				rerun(false)
+
+			// tail the stream of the target table into the output box, or stop tailing it
+			case char.CTRL_V:
+				if editing != nil {
+					continue
+				}
+				if tail != nil {
+					tail.Stop()
+					ui.Log("stopped tailing %s", tail.table)
+					outputBox.SetTitle("")
+					outputBox.Overwrite(fmt.Sprintf("stopped tailing %s", tail.table))
+					tail = nil
+					continue
+				}
+				tail = startTail(ui.db, schema.Name)
+				ui.Log("tailing %s", tail.table)
+				outputBox.SetTitle(fmt.Sprintf("Stream - %s, filtered by the search and company boxes, <Ctrl + v> to stop", tail.table))
+				outputBox.Overwrite(tail.String(tailHeight))
+
+			// hide items which have expired but haven't been deleted yet, or show them again
+			case char.CTRL_Y:
+				if editing != nil {
+					continue
+				}
+				view.ToggleExpired()
+				outputBox.Overwrite(view.String())
+
+			// profile the attributes of the target table from a sample of its items
+			case char.CTRL_A:
+				if editing != nil {
+					continue
+				}
+				prompt.Show(profileUsage, strconv.Itoa(defaultSampleSize))
+				submit = func(input string) {
+					table := schema.Name
+					var p dynamodb.TableProfile
+					err := op.run(func(ctx context.Context) (err error) {
+						p, err = ui.profile(ctx, input, table, scanWorkers)
+						return err
+					})
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					profile = &p
+					outputBox.Overwrite(describeProfile(p))
+				}
+
+			// sort the last profile by its next column
+			case char.CTRL_B:
+				if profile == nil || editing != nil {
+					continue
+				}
+				profile.Sort(profile.Order.Next())
+				outputBox.Overwrite(describeProfile(*profile))
+
+			// start staging changes, or stop and discard any staged
+			case char.CTRL_W:
+				if editing != nil {
+					continue
+				}
+				if !staging {
+					staging = true
+					outputBox.Overwrite("staging edits, deletes and clones, <Ctrl + x> to review and commit them in one transaction")
+					topText.Overwrite(header())
+					continue
+				}
+				stop := func() {
+					staged.Clear()
+					staging = false
+					topText.Overwrite(header())
+				}
+				if staged.Len() == 0 {
+					stop()
+					continue
+				}
+				confirmModal.Show(fmt.Sprintf("Discard %d staged changes?\n\n%s\n<y> to discard them and stop staging, <n> to keep them", staged.Len(), describeStaged(staged.All(), maxPlannedKeys, nil)))
+				confirm = func() {
+					stop()
+					outputBox.Overwrite("discarded staged changes")
+				}
+
+			// review the staged changes and commit them in one transaction, once confirmed
+			case char.CTRL_X:
+				if editing != nil {
+					continue
+				}
+				if staged.Len() == 0 {
+					outputBox.Overwrite("nothing is staged, <Ctrl + w> to start staging changes")
+					continue
+				}
+				changes := staged.All()
+				confirmModal.Show(fmt.Sprintf("Commit %d changes in one transaction?\n\n%s\n<y> to commit, <n> to keep staging", len(changes), describeStaged(changes, maxPlannedKeys, nil)))
+				confirm = func() {
+					err := op.run(func(ctx context.Context) error {
+						return ui.db.Transact(ctx, changes)
+					})
+					var txErr *dynamodb.TransactionError
+					if errors.As(err, &txErr) {
+						outputBox.Overwrite("transaction cancelled, nothing was written\n\n" + describeStaged(changes, 0, err))
+						return
+					}
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					ui.Log("committed %d staged changes", len(changes))
+					for _, c := range changes {
+						if c.Action == dynamodb.Delete {
+							undo.Push(ui.current(), c.Schema.Name, c.Item)
+						}
+					}
+					staged.Clear()
+					topText.Overwrite(header())
+					outputBox.Overwrite(fmt.Sprintf("committed %d changes\n\n%s", len(changes), describeStaged(changes, 0, nil)))
+				}
+
+			// repeat the search or scan in the results, skipping the cache
+			case char.CTRL_R:
+				if rerun == nil || editing != nil {
+					continue
+				}
+				rerun(true)
+
+			// discard edits
+			case char.ESCAPE:
+				if editing == nil {
+					continue
+				}
+				editing = nil
+				outputBox.AllowWrite = false
+				outputBox.SetTitle("")
+				outputBox.Overwrite(view.String())
+
 			// display output text, or app log depending, to the output box
 			case char.ENTER:
+				// new lines while editing
+				if editing != nil {
+					if selected == outputBox {
+						outputBox.Write("\n")
+					}
+					continue
+				}
+
+				// choosing a table makes it the target of later searches
+				if selected == tableList {
+					if t := tableList.Selected(); t != "" {
+						ui.db.SetTable(t)
+						ui.Log("targeting table %s", t)
+						// attributes are picked for one table at a time
+						if t != schema.Name {
+							attributes = nil
+						}
+
+						index = ""
+						var ttl dynamodb.TimeToLive
+						var ttlErr error
+						err := op.run(func(ctx context.Context) (err error) {
+							if schema, err = ui.db.Schema(ctx, t); err != nil {
+								return err
+							}
+							ttl, ttlErr = ui.db.TimeToLive(ctx, t)
+							return nil
+						})
+						if err != nil {
+							outputBox.Overwrite(err.Error())
+						}
+						if ttlErr != nil {
+							ui.Log("%v", ttlErr)
+						}
+						view.SetTTL(schema.Name, ttl)
+						fillIndexList(schema, indexList)
+						setKeyTitles(schema.KeySchema, partitionKeyBox, sortKeyBox)
+						topText.Overwrite(header())
+					}
+					continue
+				}
+
+				// choosing an index makes it the target of key queries
+				if selected == indexList {
+					if row := indexList.Selected(); row != "" {
+						index = indexName(row)
+						ui.Log("targeting index %q", index)
+						key, err := schema.Target(index)
+						if err != nil {
+							outputBox.Overwrite(err.Error())
+							continue
+						}
+						setKeyTitles(key, partitionKeyBox, sortKeyBox)
+						topText.Overwrite(header())
+					}
+					continue
+				}
+
 				// log debug info when necessary
 				if outputLog {
 					outputBox.Overwrite(ui.logger.Dump())
 					continue
 				}
 
-				// get all the integrations that match
-				// names, err := ui.db.MatchingIntegrationIDs(searchBox.Contents())
-				// if err != nil {
-				// 	outputBox.Overwrite(err.Error())
-				// 	continue
-				// }
-				integrations, err := ui.db.AllIntegrations()
-				if err != nil {
-					outputBox.Overwrite(err.Error())
+				// run PartiQL statements typed into the console
+				if selected == consoleBox {
+					statement, nextToken, page = consoleBox.Contents(), "", 0
+					var result dynamodb.StatementPage
+					err := op.run(func(ctx context.Context) (err error) {
+						result, err = ui.db.ExecuteStatement(ctx, statement, nextToken)
+						return err
+					})
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						continue
+					}
+					page++
+					nextToken = result.NextToken
+					view.Set("", result.Items, pageNote(page, nextToken))
+					outputBox.Overwrite(view.String())
 					continue
 				}
 
-				b, err := json.MarshalIndent(integrations[0], "", "  ")
+				// query by key when a partition key is given, otherwise search integrations
+				target, targetIndex := schema, index
+				hashValue, rangeCondition := partitionKeyBox.Contents(), sortKeyBox.Contents()
+				idPrefix, companyID := searchBox.Contents(), companyFilterBox.Contents()
+				projection := projectionFor(attributes, target, targetIndex)
+				rerun = func(refresh bool) {
+					var items []dynamodb.Item
+					var cache *dynamodb.CacheControl
+					err := op.run(func(ctx context.Context) (err error) {
+						ctx, cache = dynamodb.WithCacheControl(ctx, refresh)
+						if hashValue != "" {
+							items, err = ui.query(ctx, target, targetIndex, hashValue, rangeCondition, projection)
+						} else {
+							items, err = ui.search(ctx, target, targetIndex, idPrefix, companyID, projection)
+						}
+						return err
+					})
+					if err != nil {
+						outputBox.Overwrite(err.Error())
+						return
+					}
+					view.Set(target.Name, items, withCacheNote("", cache))
+					view.projection = projection
+					view.index = targetIndex
+					outputBox.Overwrite(view.String())
+				}
+				rerun(false)
+
+			// fetch the next page of the console statement
+			case char.CTRL_N:
+				if nextToken == "" || editing != nil {
+					continue
+				}
+				var result dynamodb.StatementPage
+				err := op.run(func(ctx context.Context) (err error) {
+					result, err = ui.db.ExecuteStatement(ctx, statement, nextToken)
+					return err
+				})
 				if err != nil {
 					outputBox.Overwrite(err.Error())
 					continue
 				}
-				outputBox.Overwrite(string(b))
+				page++
+				nextToken = result.NextToken
+				view.Set("", result.Items, pageNote(page, nextToken))
+				outputBox.Overwrite(view.String())
 
 			// flush the app log
 			case char.CTRL_F:
@@ -235,6 +1163,11 @@ func (ui TUI) Run(c conf.Config) error {
 			// write text to the selected box
 			default:
 				selected.Write((c))
+
+				// narrow the table list as the filter is typed
+				if selected == tableFilterBox {
+					tableList.Filter(tableFilterBox.Contents())
+				}
 			}
 		}
 	}
@@ -242,26 +1175,30 @@ func (ui TUI) Run(c conf.Config) error {
 
 // Renderable types can be rendered.
 type Renderable interface {
-	Render()
+	Drawables() []termui.Drawable
 }
 
 // MassRenderer holds types and renders them all at once.
 type MassRenderer struct {
-	all []Renderable
+	all    []Renderable
+	render func(...termui.Drawable)
 }
 
-// NewMassRenderer instantiates a new MassRenderer.
-func NewMassRenderer(r []Renderable) *MassRenderer {
+// NewMassRenderer instantiates a new MassRenderer, which draws with render.
+func NewMassRenderer(render func(...termui.Drawable), r []Renderable) *MassRenderer {
 	return &MassRenderer{
-		all: r,
+		all:    r,
+		render: render,
 	}
 }
 
-// Render all types contained.
+// Render all types contained, in order, so later types are drawn over earlier ones.
 func (r *MassRenderer) Render() {
+	var drawables []termui.Drawable
 	for i := range r.all {
-		r.all[i].Render()
+		drawables = append(drawables, r.all[i].Drawables()...)
 	}
+	r.render(drawables...)
 }
 
 func renderAll(r []interface{ Render() }) {
@@ -307,6 +1244,17 @@ func (h *SelectionHandler) Next() Writer {
 	return h.components[h.selectedIdx]
 }
 
+// Select the given component and deselect all others.  Tabbing continues from the selected component.
+func (h *SelectionHandler) Select(s Selectable) Writer {
+	for i, c := range h.components {
+		c.Deselect()
+		if c == s {
+			h.selectedIdx = i
+		}
+	}
+	return h.Next()
+}
+
 // Previous selects the previous component in the list and deselects all others.
 func (h *SelectionHandler) Previous() Writer {
 	for _, c := range h.components {
@@ -323,12 +1271,162 @@ func (h *SelectionHandler) Previous() Writer {
 	return h.components[h.selectedIdx]
 }
 
-func newTUI(db *dynamodb.DB, input chan string, l *logger.UILogger) *TUI {
+func newTUI(conn connection, connect connector, telemetry *dynamodb.Telemetry, input chan string, tokens chan chan tokenReply, l *logger.UILogger) *TUI {
 	return &TUI{
-		db:      db,
-		inputCh: input,
-		logger:  l,
+		db:          conn.db,
+		environment: conn.environment,
+		region:      conn.region,
+		connect:     connect,
+		telemetry:   telemetry,
+		inputCh:     input,
+		tokens:      tokens,
+		logger:      l,
+		render:      termui.Render,
+		dimensions:  termui.TerminalDimensions,
+	}
+}
+
+// search returns the projected attributes of all integrations in the target table matching an ID prefix and company.
+// When the target index is keyed by company the company is looked up with an index query instead of a scan.
+func (ui TUI) search(ctx context.Context, schema dynamodb.TableSchema, index, idPrefix, companyID string, projection dynamodb.Projection) ([]dynamodb.Item, error) {
+	if key, err := schema.Target(index); err == nil && index != "" && companyID != "" && key.HashKey == dynamodb.CompanyIDKey {
+		return ui.companyQuery(ctx, schema, index, key, idPrefix, companyID, projection)
+	}
+
+	integrations, err := ui.db.SearchIntegrations(ctx, idPrefix, companyID, projection)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dynamodb.Item, 0, len(integrations))
+	for _, i := range integrations {
+		item, err := i.Item()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// companyQuery looks up the integrations of a company through an index keyed by company.  The ID prefix is applied
+// as a sort key condition when the index is sorted by integration ID, otherwise to the query results.
+func (ui TUI) companyQuery(ctx context.Context, schema dynamodb.TableSchema, index string, key dynamodb.KeySchema, idPrefix, companyID string, projection dynamodb.Projection) ([]dynamodb.Item, error) {
+	q := dynamodb.KeyQuery{
+		Table:      schema.Name,
+		Index:      index,
+		Key:        key,
+		HashValue:  companyID,
+		Projection: projection,
+	}
+	if idPrefix != "" && key.RangeKey == dynamodb.IntegrationIDKey {
+		q.RangeOp = dynamodb.BeginsWith
+		q.RangeValues = []string{idPrefix}
+	}
+	items, err := ui.db.Query(ctx, q)
+	if err != nil || idPrefix == "" || q.RangeOp != "" {
+		return items, err
+	}
+
+	var matches []dynamodb.Item
+	for _, item := range items {
+		if id := item[dynamodb.IntegrationIDKey]; id != nil && id.S != nil && strings.HasPrefix(*id.S, idPrefix) {
+			matches = append(matches, item)
+		}
+	}
+	return matches, nil
+}
+
+// query runs a key query against the target table or index, reading the projected attributes of each item.  The
+// range condition is parsed from user input.
+func (ui TUI) query(ctx context.Context, schema dynamodb.TableSchema, index, hashValue, rangeCondition string, projection dynamodb.Projection) ([]dynamodb.Item, error) {
+	key, err := schema.Target(index)
+	if err != nil {
+		return nil, err
+	}
+	op, values, err := dynamodb.ParseRangeCondition(rangeCondition)
+	if err != nil {
+		return nil, err
+	}
+	return ui.db.Query(ctx, dynamodb.KeyQuery{
+		Table:       schema.Name,
+		Index:       index,
+		Key:         key,
+		HashValue:   hashValue,
+		RangeOp:     op,
+		RangeValues: values,
+		Projection:  projection,
+	})
+}
+
+// pageNote describes a page of statement results, noting when more pages are available.
+func pageNote(page int, nextToken string) string {
+	if nextToken != "" {
+		return fmt.Sprintf("page %d | <Ctrl + n> for the next page", page)
+	}
+	return fmt.Sprintf("page %d", page)
+}
+
+// save writes the changes made in edited JSON to the original item in table.
+func (ui TUI) save(ctx context.Context, table string, original dynamodb.Item, edited string) (dynamodb.Item, error) {
+	schema, err := ui.db.Schema(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := dynamodb.ApplyJSONEdits(original, []byte(edited))
+	if err != nil {
+		return nil, err
+	}
+	return ui.db.ReplaceItem(ctx, schema, original, updated)
+}
+
+// setKeyTitles names the key boxes after the keys being queried.
+func setKeyTitles(key dynamodb.KeySchema, partitionKeyBox, sortKeyBox *component.InputBox) {
+	partitionKeyBox.SetTitle("Partition Key")
+	sortKeyBox.SetTitle("Sort Key")
+	if key.HashKey != "" {
+		partitionKeyBox.SetTitle(fmt.Sprintf("Partition Key (%s)", key.HashKey))
+	}
+	if key.RangeKey != "" {
+		sortKeyBox.SetTitle(fmt.Sprintf("Sort Key (%s)", key.RangeKey))
+	}
+}
+
+// tableRow is the index list row for querying the table itself.
+const tableRow = "(table)"
+
+// fillIndexList lists the table, followed by each of its indexes, along with their key schemas.
+func fillIndexList(schema dynamodb.TableSchema, l *component.List) {
+	l.Flush()
+	l.AddRow(fmt.Sprintf("%s %s", tableRow, keyDescription(schema.KeySchema)))
+	for _, i := range schema.Indexes {
+		l.AddRow(fmt.Sprintf("%s %s %s", i.Name, i.Kind(), keyDescription(i.KeySchema)))
+	}
+}
+
+// indexName returns the index named in an index list row, or an empty string for the table itself.
+func indexName(row string) string {
+	name := strings.Fields(row)[0]
+	if name == tableRow {
+		return ""
+	}
+	return name
+}
+
+// keyDescription describes a key schema as hash[/range].
+func keyDescription(key dynamodb.KeySchema) string {
+	if key.RangeKey == "" {
+		return key.HashKey
+	}
+	return key.HashKey + "/" + key.RangeKey
+}
+
+// header returns the text shown across the top of the screen.
+func (ui TUI) header(index string) string {
+	h := fmt.Sprintf("<Ctrl + c> to quit | <Ctrl + g> to switch | env: %s | table: %s", ui.location(), ui.db.Table())
+	if index != "" {
+		h += " | index: " + index
 	}
+	return h
 }
 
 // Log writes a log message to the UI log.
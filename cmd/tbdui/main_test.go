@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/char"
+	"github.com/swtch1/tbdui/conf"
+	"github.com/swtch1/tbdui/dynamodb"
+	"github.com/swtch1/tbdui/logger"
+)
+
+// testScreen stands in for the terminal, keeping the titles and text of everything last drawn.
+type testScreen struct {
+	lock sync.Mutex
+	text string
+}
+
+// render records the widgets drawn.  It is called by Run, on the goroutine which changes the widgets.
+func (s *testScreen) render(drawables ...termui.Drawable) {
+	var b strings.Builder
+	for _, d := range drawables {
+		switch w := d.(type) {
+		case *widgets.Paragraph:
+			fmt.Fprintf(&b, "[%s]\n%s\n", w.Title, w.Text)
+		case *widgets.List:
+			fmt.Fprintf(&b, "[%s]\n%s\n", w.Title, strings.Join(w.Rows, "\n"))
+		}
+	}
+	s.lock.Lock()
+	s.text = b.String()
+	s.lock.Unlock()
+}
+
+// String returns everything last drawn.
+func (s *testScreen) String() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.text
+}
+
+// testRun is the TUI running against a fake screen, driven by key presses.
+type testRun struct {
+	t      *testing.T
+	input  chan string
+	screen *testScreen
+}
+
+// runTUI runs the TUI against db in the dev environment.  It is stopped when the test ends.
+func runTUI(t *testing.T, db dynamodb.Backend) *testRun {
+	t.Helper()
+	return runTUIWith(t, connection{db: db, environment: "dev"}, nil)
+}
+
+// runTUIWith runs the TUI against conn, switching environments through connect.  It is stopped when the test ends.
+func runTUIWith(t *testing.T, conn connection, connect connector) *testRun {
+	t.Helper()
+	r := &testRun{t: t, input: make(chan string), screen: &testScreen{}}
+	ui := newTUI(conn, connect, dynamodb.NewTelemetry(), r.input, make(chan chan tokenReply), logger.NewUILogger())
+	ui.render = r.screen.render
+	ui.dimensions = func() (int, int) { return 240, 80 }
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ui.Run(conf.NewDefault())
+	}()
+	t.Cleanup(func() {
+		close(r.input)
+		require.NoError(t, <-done)
+	})
+	return r
+}
+
+// press sends keys to the TUI one at a time.  Keys pressed while a backend call runs are ignored, so wait for its
+// results to be drawn before pressing more.
+func (r *testRun) press(keys ...string) {
+	for _, k := range keys {
+		r.input <- k
+	}
+}
+
+// typeText presses the key for each character of text.
+func (r *testRun) typeText(text string) {
+	for _, c := range text {
+		r.press(string(c))
+	}
+}
+
+// waitFor waits for text to be drawn, failing the test if it isn't soon.
+func (r *testRun) waitFor(text string) {
+	r.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(r.screen.String(), text) {
+		if time.Now().After(deadline) {
+			r.t.Fatalf("%q was never drawn, the screen is:\n%s", text, r.screen)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunSearch(t *testing.T) {
+	t.Parallel()
+
+	db, err := dynamodb.NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	r := runTUI(t, db)
+	r.waitFor("dev-integrations")
+
+	r.typeText("int-slack")
+	r.press(char.ENTER)
+	r.waitFor(`"name": "Slack Alerts"`)
+	require.NotContains(t, r.screen.String(), "Salesforce Sync")
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+)
+
+// errMFACancelled is returned when the user dismisses the MFA prompt.
+var errMFACancelled = errors.New("MFA token entry cancelled")
+
+// tokenReply answers a request for an MFA token code.
+type tokenReply struct {
+	code string
+	err  error
+}
+
+// mfaPrompt asks the user for MFA token codes.
+//
+// The AWS SDK asks for a token when credentials are first needed, in the middle of a backend call.  The request is
+// handed to the running operation, which owns the input, to show a masked prompt.  Calls made in the background, like
+// describing tables and tailing streams, can ask while no operation is running, so the TUI loop answers requests
+// through the operation too while it waits for input.
+type mfaPrompt struct {
+	requests chan chan tokenReply
+}
+
+// Token blocks until a token code is submitted, or the prompt is dismissed.
+func (m mfaPrompt) Token() (string, error) {
+	reply := make(chan tokenReply)
+	m.requests <- reply
+	r := <-reply
+	return r.code, r.err
+}
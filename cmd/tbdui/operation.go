@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/swtch1/tbdui/char"
+	"github.com/swtch1/tbdui/component"
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// errCancelled is returned by operations cancelled with Escape.
+var errCancelled = errors.New("operation cancelled")
+
+// mfaTitle is the title of the MFA prompt.
+const mfaTitle = "MFA token code | <Enter> to submit, <Escape> to cancel"
+
+// operation runs backend calls off the TUI loop, so they can be cancelled with Escape.  While a call runs the
+// operation owns the input, shows its progress, like throttling retries, in the status line and answers MFA prompts,
+// including those of calls made in the background.
+//
+// The call runs on another goroutine, so it must only touch the backend and its own variables.  Components are
+// updated from its results once run returns.
+type operation struct {
+	inputCh chan string
+	// tokens are requests for MFA token codes from the backend
+	tokens chan chan tokenReply
+	status *component.Info
+	// header is the status line when nothing is running
+	header func() string
+	mfa    *component.Prompt
+	render func()
+}
+
+// run calls f with a context which is cancelled when Escape is pressed, or the input is closed, and waits for it to
+// return.
+func (o operation) run(f func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// only the latest status is shown, however often it is reported
+	var lock sync.Mutex
+	var latest string
+	updated := make(chan struct{}, 1)
+	ctx = dynamodb.WithStatus(ctx, func(s string) {
+		lock.Lock()
+		latest = s
+		lock.Unlock()
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f(ctx)
+	}()
+
+	// nothing more can be typed once the input is closed, so the call is cancelled
+	input := o.inputCh
+
+	defer func() {
+		o.status.Overwrite(o.header())
+	}()
+	o.show("working, <Escape> to cancel")
+	var reply chan tokenReply
+	for {
+		// only one token is asked for at a time, other requests wait until it is answered
+		tokens := o.tokens
+		if o.mfa.Visible() {
+			tokens = nil
+		}
+
+		select {
+		case err := <-done:
+			if err != nil && ctx.Err() != nil {
+				return errCancelled
+			}
+			return err
+
+		case <-updated:
+			lock.Lock()
+			o.show(latest + ", <Escape> to cancel")
+			lock.Unlock()
+
+		case reply = <-tokens:
+			if input == nil {
+				reply <- tokenReply{err: errMFACancelled}
+				break
+			}
+			o.mfa.Show(mfaTitle, "")
+
+		case c, ok := <-input:
+			if !ok {
+				input = nil
+				cancel()
+				if o.mfa.Visible() {
+					o.mfa.Hide()
+					reply <- tokenReply{err: errMFACancelled}
+				}
+				break
+			}
+			// the MFA prompt takes all input while it is shown
+			if o.mfa.Visible() {
+				o.mfaInput(reply, c)
+				break
+			}
+			if c == char.ESCAPE {
+				cancel()
+				o.show("cancelling")
+			}
+		}
+		o.render()
+	}
+}
+
+// answer asks for an MFA token code requested by a backend call made in the background, like describing a table or
+// tailing a stream, while no operation is running.  The prompt takes all input until the code is submitted or the
+// prompt is dismissed.
+func (o operation) answer(reply chan tokenReply) {
+	o.mfa.Show(mfaTitle, "")
+	for o.mfa.Visible() {
+		o.render()
+		c, ok := <-o.inputCh
+		if !ok {
+			o.mfa.Hide()
+			reply <- tokenReply{err: errMFACancelled}
+			break
+		}
+		o.mfaInput(reply, c)
+	}
+	o.render()
+}
+
+// mfaInput handles input to the MFA prompt, answering reply once a code is submitted or the prompt is dismissed.
+func (o operation) mfaInput(reply chan tokenReply, c string) {
+	switch c {
+	case char.ENTER:
+		o.mfa.Hide()
+		reply <- tokenReply{code: o.mfa.Contents()}
+	case char.ESCAPE:
+		o.mfa.Hide()
+		reply <- tokenReply{err: errMFACancelled}
+	default:
+		o.mfa.Write(c)
+	}
+}
+
+// show a status beside the header.
+func (o operation) show(status string) {
+	o.status.Overwrite(o.header() + " | " + status)
+	o.render()
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/char"
+	"github.com/swtch1/tbdui/component"
+	"github.com/swtch1/tbdui/conf"
+)
+
+// newTestOperation returns an operation which takes input from the returned channel and renders nothing.
+func newTestOperation() (operation, chan string) {
+	input := make(chan string)
+	return operation{
+		inputCh: input,
+		tokens:  make(chan chan tokenReply),
+		status:  component.NewInfo("", component.Dimensions{}),
+		header:  func() string { return "" },
+		mfa:     component.NewPrompt(conf.Config{}, component.Dimensions{}),
+		render:  func() {},
+	}, input
+}
+
+func TestOperationAnswer(t *testing.T) {
+	t.Parallel()
+
+	o, input := newTestOperation()
+	mfa := mfaPrompt{requests: o.tokens}
+
+	// a background call asks for a token while nothing is running
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		code, err := mfa.Token()
+		results <- result{code, err}
+	}()
+	reply := <-o.tokens
+	go func() {
+		for _, c := range []string{"1", "2", "3", char.ENTER} {
+			input <- c
+		}
+	}()
+	o.answer(reply)
+	require.Equal(t, result{code: "123"}, <-results)
+	require.False(t, o.mfa.Visible())
+
+	// dismissing the prompt fails the call
+	go func() {
+		code, err := mfa.Token()
+		results <- result{code, err}
+	}()
+	reply = <-o.tokens
+	go func() {
+		input <- char.ESCAPE
+	}()
+	o.answer(reply)
+	require.Equal(t, result{err: errMFACancelled}, <-results)
+}
+
+func TestOperationRunAnswersBackgroundTokens(t *testing.T) {
+	t.Parallel()
+
+	o, input := newTestOperation()
+	mfa := mfaPrompt{requests: o.tokens}
+	shown := make(chan struct{}, 1)
+	o.render = func() {
+		if o.mfa.Visible() {
+			select {
+			case shown <- struct{}{}:
+			default:
+			}
+		}
+	}
+
+	// a token asked for by a background call is answered while an operation runs
+	background := make(chan string, 1)
+	go func() {
+		code, _ := mfa.Token()
+		background <- code
+	}()
+	var code string
+	err := o.run(func(ctx context.Context) error {
+		<-shown
+		for _, c := range []string{"4", "2", char.ENTER} {
+			input <- c
+		}
+		code = <-background
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "42", code)
+}
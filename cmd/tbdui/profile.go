@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// profileUsage is the title of the profile prompt.
+const profileUsage = "Profile - <number of items to sample>, or all to scan the whole table"
+
+// defaultSampleSize is offered in the profile prompt.
+const defaultSampleSize = 1000
+
+// profile infers the attributes of table from a sample of its items, or from all of them.  Input is the sample size,
+// or "all" to scan every item by segments in parallel.
+func (ui TUI) profile(ctx context.Context, input, table string, segments int) (dynamodb.TableProfile, error) {
+	input = strings.TrimSpace(input)
+	ctx = fresh(ctx)
+	var items []dynamodb.Item
+	var err error
+	if input == "all" {
+		items, err = ui.db.ParallelScan(ctx, table, segments, nil)
+	} else {
+		size, convErr := strconv.Atoi(input)
+		if convErr != nil || size < 1 {
+			return dynamodb.TableProfile{}, fmt.Errorf("expected a sample size or all, got %q", input)
+		}
+		items, err = ui.db.Sample(ctx, table, size)
+	}
+	if err != nil {
+		return dynamodb.TableProfile{}, err
+	}
+	ui.Log("profiled %d items from %s", len(items), table)
+	return dynamodb.ProfileItems(table, items), nil
+}
+
+// describeProfile draws a profile as a table with a row for each attribute.  Attributes found with more than one type
+// are highlighted.
+func describeProfile(p dynamodb.TableProfile) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "profile of %s: %d attributes in %d items, sorted by %s | <Ctrl + b> to sort by %s\n\n",
+		p.Table, len(p.Attributes), p.Items, p.Order, p.Order.Next())
+	if len(p.Attributes) == 0 {
+		return b.String()
+	}
+
+	rows := [][]string{{"PATH", "SEEN", "TYPES", "EXAMPLES"}}
+	for _, a := range p.Attributes {
+		types := a.TypeNames()
+		for i, t := range types {
+			if a.Mixed() {
+				types[i] = fmt.Sprintf("%s %d", t, a.Types[t])
+			}
+		}
+		rows = append(rows, []string{
+			a.Path,
+			fmt.Sprintf("%d %3.0f%%", a.Count, 100*float64(a.Count)/float64(p.Items)),
+			strings.Join(types, ", "),
+			strings.Join(a.Examples, ", "),
+		})
+	}
+
+	// every column but the last is padded to its widest value
+	widths := make([]int, len(rows[0])-1)
+	for _, r := range rows {
+		for i := range widths {
+			if len(r[i]) > widths[i] {
+				widths[i] = len(r[i])
+			}
+		}
+	}
+	for n, r := range rows {
+		var line strings.Builder
+		for i, w := range widths {
+			fmt.Fprintf(&line, "%-*s  ", w, r[i])
+		}
+		line.WriteString(r[len(r)-1])
+		if n > 0 && p.Attributes[n-1].Mixed() {
+			fmt.Fprintf(&b, "[%s](fg:yellow)\n", line.String())
+			continue
+		}
+		b.WriteString(line.String() + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// projectionUsage is the title of the attribute picker.
+const projectionUsage = "Attributes - <attribute, ...> to fetch on searches and scans, e.g. name, config.channel, or nothing for whole items"
+
+// projectionFor returns the attributes to fetch from a table or one of its indexes, with the keys of both added so
+// projected items can still be edited, deleted and cloned.
+func projectionFor(attributes dynamodb.Projection, schema dynamodb.TableSchema, index string) dynamodb.Projection {
+	p := attributes.WithKeys(schema.KeySchema)
+	if key, err := schema.Target(index); err == nil {
+		p = p.WithKeys(key)
+	}
+	return p
+}
+
+// projectionNote describes the attributes fetched by searches and scans.
+func projectionNote(attributes dynamodb.Projection) string {
+	if len(attributes) == 0 {
+		return "searches and scans fetch whole items"
+	}
+	return fmt.Sprintf("searches and scans fetch only %s, and the keys of each item", attributes)
+}
+
+// wholeItem reads the whole of a partial item from the table by its key, since writing a partial item back would
+// lose the attributes it is missing.  Whole items are returned as they are.
+func (ui TUI) wholeItem(ctx context.Context, table string, item dynamodb.Item, partial bool) (dynamodb.Item, error) {
+	if !partial {
+		return item, nil
+	}
+	schema, err := ui.db.Schema(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	items, err := ui.db.GetItems(ctx, schema, []dynamodb.Item{schema.KeyOf(item)})
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("item in %s: %w", table, dynamodb.ErrNotFound)
+	}
+	return items[0], nil
+}
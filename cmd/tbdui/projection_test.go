@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/char"
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// keysOnlyFixtures is a table with a KEYS_ONLY index, so items queried from the index are missing most of their
+// attributes.
+const keysOnlyFixtures = `{"environment": "dev", "tables": [{"name": "dev-integrations", "hash_key": "integration_id",
+	"indexes": [{"name": "company_id-index", "hash_key": "company_id", "projection": "KEYS_ONLY"}],
+	"items": [{"integration_id": "int-slack-001", "company_id": "acme", "name": "Slack Alerts", "enabled": true, "version": 1,
+		"config": {"channel": "#alerts"}}]}]}`
+
+func TestEditIndexResult(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := dynamodb.NewMemoryDB("dev")
+	require.NoError(t, db.LoadFixtures(strings.NewReader(keysOnlyFixtures)))
+	ui := TUI{db: db}
+	schema, err := db.Schema(ctx, "dev-integrations")
+	require.NoError(t, err)
+
+	items, err := ui.query(ctx, schema, "company_id-index", "acme", "", nil)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.NotContains(t, items[0].Names(), "config")
+	var view resultView
+	view.Set(schema.Name, items, "")
+	view.index = "company_id-index"
+	require.True(t, view.Partial())
+
+	// the whole item is edited, so saving it loses nothing
+	current, _ := view.Current()
+	item, err := ui.wholeItem(ctx, view.table, current, view.Partial())
+	require.NoError(t, err)
+	require.Contains(t, item.Names(), "config")
+	edited := item.Copy()
+	edited["name"] = &ddb.AttributeValue{S: aws.String("Slack Pages")}
+	_, err = ui.save(ctx, view.table, item, formatItem(edited))
+	require.NoError(t, err)
+
+	stored, err := db.GetItems(ctx, schema, []dynamodb.Item{schema.KeyOf(item)})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, "Slack Pages", *stored[0]["name"].S)
+	require.Equal(t, "#alerts", *stored[0]["config"].M["channel"].S)
+	require.True(t, *stored[0]["enabled"].BOOL)
+	require.Equal(t, "2", *stored[0][dynamodb.VersionKey].N)
+}
+
+func TestCloneIndexResult(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := dynamodb.NewMemoryDB("dev")
+	require.NoError(t, db.LoadFixtures(strings.NewReader(keysOnlyFixtures)))
+	ui := TUI{db: db, environment: "dev"}
+	schema, err := db.Schema(ctx, "dev-integrations")
+	require.NoError(t, err)
+
+	items, err := ui.query(ctx, schema, "company_id-index", "acme", "", nil)
+	require.NoError(t, err)
+	var view resultView
+	view.Set(schema.Name, items, "")
+	view.index = "company_id-index"
+
+	// the whole item is cloned, not just the attributes the index holds
+	current, _ := view.Current()
+	item, err := ui.wholeItem(ctx, view.table, current, view.Partial())
+	require.NoError(t, err)
+	plan, err := ui.planClone(ctx, "dev integration_id=int-slack-002", view.table, item)
+	require.NoError(t, err)
+	require.Equal(t, "int-slack-002", *plan.item["integration_id"].S)
+	require.Equal(t, "Slack Alerts", *plan.item["name"].S)
+	require.Equal(t, "#alerts", *plan.item["config"].M["channel"].S)
+}
+
+func TestRunEditIndexResult(t *testing.T) {
+	t.Parallel()
+
+	db := dynamodb.NewMemoryDB("dev")
+	require.NoError(t, db.LoadFixtures(strings.NewReader(keysOnlyFixtures)))
+	r := runTUI(t, db)
+	r.waitFor("company_id-index GSI")
+
+	// query the index for a company, from the partition key box
+	r.press(char.TAB, char.TAB)
+	r.typeText("acme")
+	r.press(char.TAB, char.TAB, char.DOWN, char.ENTER)
+	r.waitFor("index: company_id-index")
+	r.press(char.TAB, char.ENTER)
+	r.waitFor(`"company_id": "acme"`)
+	require.NotContains(t, r.screen.String(), "Slack Alerts")
+
+	// the whole item is edited and saved, not just the keys the index holds
+	r.press(char.CTRL_E)
+	r.waitFor("Slack Alerts")
+	r.press(char.CTRL_S)
+	r.waitFor(`"version": 2`)
+
+	schema, err := db.Schema(context.Background(), "dev-integrations")
+	require.NoError(t, err)
+	stored, err := db.GetItems(context.Background(), schema, []dynamodb.Item{{dynamodb.IntegrationIDKey: &ddb.AttributeValue{S: aws.String("int-slack-001")}}})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, "Slack Alerts", *stored[0]["name"].S)
+	require.Equal(t, "#alerts", *stored[0]["config"].M["channel"].S)
+	require.True(t, *stored[0]["enabled"].BOOL)
+	require.Equal(t, "2", *stored[0][dynamodb.VersionKey].N)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// resultView holds the items shown in the output box and which one of them is current.
+type resultView struct {
+	items   []dynamodb.Item
+	current int
+	// table the items were read from.  It is empty when the table is not known, e.g. for PartiQL results.
+	table string
+	// note is shown above the items, e.g. to hint at paging.
+	note string
+	// ttls are the TTL configurations of tables, by name, used to show when their items expire.
+	ttls map[string]dynamodb.TimeToLive
+	// hideExpired skips items which have expired but haven't been deleted yet.
+	hideExpired bool
+	// projection the items were read with.  Items are incomplete unless it is empty.
+	projection dynamodb.Projection
+	// index the items were queried from, if any.  Items from an index are incomplete unless it projects everything.
+	index string
+}
+
+// Set the items in the view, making the first one current.
+func (v *resultView) Set(table string, items []dynamodb.Item, note string) {
+	v.items = items
+	v.current = 0
+	v.table = table
+	v.note = note
+	v.projection = nil
+	v.index = ""
+}
+
+// Partial reports whether the items may be missing attributes, because they were read with a projection or from an
+// index.
+func (v *resultView) Partial() bool {
+	return len(v.projection) > 0 || v.index != ""
+}
+
+// SetTTL records the TTL configuration of a table, so the expiry of its items can be shown.
+func (v *resultView) SetTTL(table string, ttl dynamodb.TimeToLive) {
+	if v.ttls == nil {
+		v.ttls = make(map[string]dynamodb.TimeToLive)
+	}
+	v.ttls[table] = ttl
+}
+
+// ToggleExpired hides expired items, or shows them again.
+func (v *resultView) ToggleExpired() {
+	v.hideExpired = !v.hideExpired
+}
+
+// hidden reports whether item i is skipped because it has expired.
+func (v *resultView) hidden(i int) bool {
+	return v.hideExpired && v.ttls[v.table].Expired(v.items[i], time.Now())
+}
+
+// expired counts the items which have expired but haven't been deleted yet.
+func (v *resultView) expired() int {
+	n := 0
+	for _, item := range v.items {
+		if v.ttls[v.table].Expired(item, time.Now()) {
+			n++
+		}
+	}
+	return n
+}
+
+// settle moves from a hidden item to the next shown one, if there is one.
+func (v *resultView) settle() {
+	for i := 0; i < len(v.items) && v.hidden(v.current); i++ {
+		v.current = (v.current + 1) % len(v.items)
+	}
+}
+
+// Current returns the current item.  False is returned when the view is empty, or every item is hidden.
+func (v *resultView) Current() (dynamodb.Item, bool) {
+	v.settle()
+	if len(v.items) == 0 || v.hidden(v.current) {
+		return nil, false
+	}
+	return v.items[v.current], true
+}
+
+// Replace the current item, e.g. after it has been edited.
+func (v *resultView) Replace(item dynamodb.Item) {
+	if len(v.items) == 0 {
+		return
+	}
+	v.items[v.current] = item
+}
+
+// Remove the current item, e.g. after it has been deleted.
+func (v *resultView) Remove() {
+	if len(v.items) == 0 {
+		return
+	}
+	v.items = append(v.items[:v.current], v.items[v.current+1:]...)
+	if v.current == len(v.items) && v.current > 0 {
+		v.current--
+	}
+}
+
+// Insert an item before the current item, making it current.
+func (v *resultView) Insert(item dynamodb.Item) {
+	v.items = append(v.items[:v.current], append([]dynamodb.Item{item}, v.items[v.current:]...)...)
+}
+
+// Next makes the next shown item current, wrapping at the end.
+func (v *resultView) Next() {
+	for i := 0; i < len(v.items); i++ {
+		v.current = (v.current + 1) % len(v.items)
+		if !v.hidden(v.current) {
+			return
+		}
+	}
+}
+
+// Previous makes the previous shown item current, wrapping at the start.
+func (v *resultView) Previous() {
+	for i := 0; i < len(v.items); i++ {
+		v.current = (v.current - 1 + len(v.items)) % len(v.items)
+		if !v.hidden(v.current) {
+			return
+		}
+	}
+}
+
+// String formats the current item for the output box.
+func (v *resultView) String() string {
+	var text string
+	if v.note != "" {
+		text = v.note + "\n"
+	}
+	var expiry string
+	switch n := v.expired(); {
+	case n > 0 && v.hideExpired:
+		expiry = fmt.Sprintf(" | %d expired hidden, <Ctrl + y> to show", n)
+	case n > 0:
+		expiry = fmt.Sprintf(" | <Ctrl + y> to hide %d expired", n)
+	}
+	item, ok := v.Current()
+	if !ok {
+		return text + "no matching items" + expiry
+	}
+
+	// the position counts shown items only
+	position, shown := 0, 0
+	for i := range v.items {
+		if v.hidden(i) {
+			continue
+		}
+		shown++
+		if i <= v.current {
+			position++
+		}
+	}
+	text += fmt.Sprintf("item %d of %d%s | <PageUp>/<PageDown> to browse, <Ctrl + e> to edit, <Ctrl + d> to delete, <Ctrl + o> to export, <Ctrl + p> to clone\n", position, shown, expiry)
+	if line := expiryLine(v.ttls[v.table], item, time.Now()); line != "" {
+		text += line + "\n"
+	}
+	return text + formatItem(item)
+}
+
+// expiryLine describes when an item expires, or how long ago it did if it is still waiting to be deleted.  Nothing is
+// returned for items which never expire.
+func expiryLine(ttl dynamodb.TimeToLive, item dynamodb.Item, now time.Time) string {
+	at, ok := ttl.ExpiresAt(item)
+	if !ok {
+		return ""
+	}
+	when := at.Local().Format("2006-01-02 15:04:05 MST")
+	if at.Before(now) {
+		return fmt.Sprintf("[%s: %s, expired %s ago but not deleted yet](fg:red)", ttl.Attribute, when, countdown(now.Sub(at)))
+	}
+	return fmt.Sprintf("%s: %s, expires in %s", ttl.Attribute, when, countdown(at.Sub(now)))
+}
+
+// countdown describes a duration in its two largest units, e.g. "3h12m".
+func countdown(d time.Duration) string {
+	days, hours, minutes, seconds := int(d.Hours())/24, int(d.Hours())%24, int(d.Minutes())%60, int(d.Seconds())%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm%ds", minutes, seconds)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
+// formatItem formats an item as indented JSON.
+func formatItem(item dynamodb.Item) string {
+	b, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+// withCacheNote adds how old cached results are to a note, if any were used.
+func withCacheNote(note string, cache *dynamodb.CacheControl) string {
+	at, ok := cache.CachedAt()
+	if !ok {
+		return note
+	}
+	cached := fmt.Sprintf("cached %s ago | <Ctrl + r> to refresh", age(time.Since(at)))
+	if note == "" {
+		return cached
+	}
+	return note + " | " + cached
+}
+
+// fresh returns a context whose reads skip cached results, for one-off reads like comparisons, exports and profiles
+// whose results can't be refreshed with <Ctrl + r>.  What they read is still cached for later searches.
+func fresh(ctx context.Context) context.Context {
+	ctx, _ = dynamodb.WithCacheControl(ctx, true)
+	return ctx
+}
+
+// age describes a duration in its largest whole unit, e.g. "2m".
+func age(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/dynamodb"
+	"github.com/swtch1/tbdui/logger"
+)
+
+const testFixtures = "../../test/fixtures/dev.json"
+
+func TestOneOffReadsSkipTheCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := dynamodb.NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	cache := dynamodb.NewCache(db, time.Hour)
+	ui := TUI{db: cache, logger: logger.NewUILogger()}
+	schema, err := db.Schema(ctx, "dev-integrations")
+	require.NoError(t, err)
+
+	// results are cached, then the table changes behind the cache
+	scanned, err := cache.ParallelScan(ctx, schema.Name, 2, nil)
+	require.NoError(t, err)
+	_, err = cache.Describe(ctx, schema.Name)
+	require.NoError(t, err)
+	added := dynamodb.Item{dynamodb.IntegrationIDKey: &ddb.AttributeValue{S: aws.String("int-new-001")}}
+	require.NoError(t, db.CreateItem(ctx, schema, added))
+
+	profile, err := ui.profile(ctx, "all", schema.Name, 2)
+	require.NoError(t, err)
+	require.Equal(t, len(scanned)+1, profile.Items)
+
+	describer := newTableDescriber()
+	describer.Describe(ui.db, schema.Name)
+	d := <-describer.results
+	require.NoError(t, d.err)
+	require.Equal(t, int64(len(scanned)+1), d.info.Items)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// stagedChanges are writes waiting to be committed together in a single transaction, in the order they were staged.
+type stagedChanges struct {
+	changes []dynamodb.StagedChange
+}
+
+// Add stages a change.  A change already staged for the same item is replaced, since a transaction can only change
+// each item once.
+func (s *stagedChanges) Add(c dynamodb.StagedChange) {
+	for i, staged := range s.changes {
+		if sameItem(staged, c) {
+			s.changes[i] = c
+			return
+		}
+	}
+	s.changes = append(s.changes, c)
+}
+
+// All returns the staged changes.
+func (s *stagedChanges) All() []dynamodb.StagedChange {
+	return append([]dynamodb.StagedChange(nil), s.changes...)
+}
+
+// Clear discards every staged change.
+func (s *stagedChanges) Clear() {
+	s.changes = nil
+}
+
+// Len returns the number of staged changes.
+func (s *stagedChanges) Len() int {
+	return len(s.changes)
+}
+
+// sameItem reports whether two changes are to the same item.
+func sameItem(a, b dynamodb.StagedChange) bool {
+	return a.Schema.Name == b.Schema.Name && a.Schema.SameKey(a.Item, b.Item)
+}
+
+// stageEdit applies the edited JSON to original, like save, but stages the write instead of making it.
+func (ui TUI) stageEdit(ctx context.Context, table string, original dynamodb.Item, edited string) (dynamodb.StagedChange, error) {
+	schema, err := ui.db.Schema(ctx, table)
+	if err != nil {
+		return dynamodb.StagedChange{}, err
+	}
+	updated, err := dynamodb.ApplyJSONEdits(original, []byte(edited))
+	if err != nil {
+		return dynamodb.StagedChange{}, err
+	}
+	return dynamodb.StageEdit(schema, original, updated)
+}
+
+// stagedNote describes a newly staged change and how many are waiting.
+func stagedNote(c dynamodb.StagedChange, staged int) string {
+	return fmt.Sprintf("staged %s | %d staged, <Ctrl + x> to review and commit", c, staged)
+}
+
+// describeStaged lists changes, at most limit of them when limit is above zero.  When the transaction was cancelled
+// the reason is given next to each change which caused it.
+func describeStaged(changes []dynamodb.StagedChange, limit int, err error) string {
+	var txErr *dynamodb.TransactionError
+	errors.As(err, &txErr)
+
+	var b strings.Builder
+	for i, c := range changes {
+		if limit > 0 && i == limit {
+			fmt.Fprintf(&b, "  ...and %d more\n", len(changes)-limit)
+			break
+		}
+		line := fmt.Sprintf("%d. %s", i+1, c)
+		if txErr != nil {
+			if reason := txErr.Reason(i); reason != nil {
+				fmt.Fprintf(&b, "[%s - %v](fg:red)\n", line, reason)
+				continue
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
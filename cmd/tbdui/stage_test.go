@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+func TestStagedChanges(t *testing.T) {
+	t.Parallel()
+
+	integrations := dynamodb.TableSchema{Name: "dev-integrations", KeySchema: dynamodb.KeySchema{HashKey: "integration_id", HashKeyType: "S"}}
+	mappings := dynamodb.TableSchema{Name: "dev-company-mappings", KeySchema: dynamodb.KeySchema{HashKey: "integration_id", HashKeyType: "S"}}
+	item := func(id, name string) dynamodb.Item {
+		return dynamodb.Item{"integration_id": {S: aws.String(id)}, "name": {S: aws.String(name)}}
+	}
+
+	var s stagedChanges
+	s.Add(dynamodb.StagedChange{Action: dynamodb.Delete, Schema: integrations, Item: item("a", "first")})
+	s.Add(dynamodb.StagedChange{Action: dynamodb.Put, Schema: integrations, Item: item("b", "second")})
+	// the same key in another table is another item
+	s.Add(dynamodb.StagedChange{Action: dynamodb.Put, Schema: mappings, Item: item("a", "mapping")})
+	require.Equal(t, 3, s.Len())
+
+	// a later change to the same item replaces the earlier one, in its place
+	s.Add(dynamodb.StagedChange{Action: dynamodb.Put, Schema: integrations, Item: item("a", "again")})
+	require.Equal(t, 3, s.Len())
+	all := s.All()
+	require.Equal(t, dynamodb.Put, all[0].Action)
+	require.Equal(t, "again", *all[0].Item["name"].S)
+
+	// the changes returned are a copy
+	all[0] = dynamodb.StagedChange{}
+	require.Equal(t, dynamodb.Put, s.All()[0].Action)
+
+	s.Clear()
+	require.Equal(t, 0, s.Len())
+	require.Empty(t, s.All())
+}
+
+func TestStagedChangesNumericKeys(t *testing.T) {
+	t.Parallel()
+
+	schema := dynamodb.TableSchema{Name: "dev-counters", KeySchema: dynamodb.KeySchema{HashKey: "id", HashKeyType: "N"}}
+	item := func(id, name string) dynamodb.Item {
+		return dynamodb.Item{"id": {N: aws.String(id)}, "name": {S: aws.String(name)}}
+	}
+
+	var s stagedChanges
+	s.Add(dynamodb.StagedChange{Action: dynamodb.Put, Schema: schema, Item: item("1", "first")})
+	// "1.0" is the same number, so the same item
+	s.Add(dynamodb.StagedChange{Action: dynamodb.Delete, Schema: schema, Item: item("1.0", "again")})
+	require.Equal(t, 1, s.Len())
+	require.Equal(t, dynamodb.Delete, s.All()[0].Action)
+
+	s.Add(dynamodb.StagedChange{Action: dynamodb.Put, Schema: schema, Item: item("10", "other")})
+	require.Equal(t, 2, s.Len())
+}
+
+func TestDescribeStaged(t *testing.T) {
+	t.Parallel()
+
+	schema := dynamodb.TableSchema{Name: "dev-integrations", KeySchema: dynamodb.KeySchema{HashKey: "integration_id", HashKeyType: "S"}}
+	var changes []dynamodb.StagedChange
+	for _, id := range []string{"a", "b", "c"} {
+		changes = append(changes, dynamodb.StagedChange{Action: dynamodb.Delete, Schema: schema, Item: dynamodb.Item{"integration_id": &ddb.AttributeValue{S: aws.String(id)}}})
+	}
+
+	require.Equal(t, `1. delete in dev-integrations {"integration_id":"a"}
+2. delete in dev-integrations {"integration_id":"b"}
+3. delete in dev-integrations {"integration_id":"c"}
+`, describeStaged(changes, 0, nil))
+
+	require.Equal(t, `1. delete in dev-integrations {"integration_id":"a"}
+2. delete in dev-integrations {"integration_id":"b"}
+  ...and 1 more
+`, describeStaged(changes, 2, nil))
+
+	// the changes which cancelled a transaction are marked with why
+	err := &dynamodb.TransactionError{Changes: changes, Reasons: []dynamodb.CancellationReason{{Code: "None"}, {Code: "ConditionalCheckFailed"}, {Code: "None"}}}
+	require.Equal(t, `1. delete in dev-integrations {"integration_id":"a"}
+[2. delete in dev-integrations {"integration_id":"b"} - item not found](fg:red)
+3. delete in dev-integrations {"integration_id":"c"}
+`, describeStaged(changes, 0, err))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// tableDescription is the result of describing a table in the background.
+type tableDescription struct {
+	table string
+	info  dynamodb.TableInfo
+	err   error
+	// request counts the descriptions started by the describer, up to this one
+	request int
+}
+
+// tableDescriber describes the table highlighted in the table list in the background, so the list can be browsed
+// while descriptions are read.  Only the latest description is delivered.
+type tableDescriber struct {
+	results chan tableDescription
+	cancel  context.CancelFunc
+	// requests counts the descriptions started, so results of abandoned ones can be told apart
+	requests int
+}
+
+// newTableDescriber returns a describer which delivers descriptions on its results channel.
+func newTableDescriber() *tableDescriber {
+	return &tableDescriber{results: make(chan tableDescription)}
+}
+
+// Describe starts describing table, abandoning any description still being read.
+func (d *tableDescriber) Describe(db dynamodb.Backend, table string) {
+	d.Stop()
+	ctx, cancel := context.WithCancel(fresh(context.Background()))
+	d.cancel = cancel
+	d.requests++
+	request := d.requests
+	go func() {
+		info, err := db.Describe(ctx, table)
+		select {
+		case d.results <- tableDescription{table: table, info: info, err: err, request: request}:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// Stop abandons any description still being read.
+func (d *tableDescriber) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.requests++
+}
+
+// Latest returns true when r is the result of the latest description started, and it hasn't been abandoned since.
+func (d *tableDescriber) Latest(r tableDescription) bool {
+	return r.request == d.requests
+}
+
+// describeTable lists the metadata of a table for the table panel.
+func describeTable(info dynamodb.TableInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", info.Name, strings.ToLower(info.Status))
+	if !info.Created.IsZero() {
+		fmt.Fprintf(&b, "created %s\n", info.Created.Local().Format("2006-01-02 15:04 MST"))
+	}
+
+	fmt.Fprintf(&b, "\npartition key: %s\n", keyAttribute(info.HashKey, info.HashKeyType))
+	if info.RangeKey != "" {
+		fmt.Fprintf(&b, "sort key: %s\n", keyAttribute(info.RangeKey, info.RangeKeyType))
+	} else {
+		b.WriteString("sort key: none\n")
+	}
+
+	if len(info.Indexes) > 0 {
+		b.WriteString("\nindexes:\n")
+	}
+	for _, i := range info.Indexes {
+		projection := i.Projection
+		if len(i.ProjectedAttributes) > 0 {
+			projection += " " + strings.Join(i.ProjectedAttributes, ", ")
+		}
+		fmt.Fprintf(&b, "  %s %s\n    %s, projects %s\n", i.Kind(), i.Name, keyDescription(i.KeySchema), projection)
+		if c, ok := info.IndexCapacity[i.Name]; ok && !info.OnDemand {
+			fmt.Fprintf(&b, "    provisioned %d RCU, %d WCU\n", c.Read, c.Write)
+		}
+	}
+
+	if info.OnDemand {
+		b.WriteString("\nbilling: on demand\n")
+	} else {
+		fmt.Fprintf(&b, "\nbilling: provisioned %d RCU, %d WCU\n", info.Capacity.Read, info.Capacity.Write)
+	}
+	fmt.Fprintf(&b, "size: about %d items, %s\n", info.Items, byteSize(info.SizeBytes))
+
+	if info.StreamEnabled {
+		fmt.Fprintf(&b, "stream: %s\n", info.StreamView)
+	} else {
+		b.WriteString("stream: disabled\n")
+	}
+	switch {
+	case info.TTL.Status == "":
+		b.WriteString("ttl: unknown\n")
+	case info.TTL.Attribute != "":
+		fmt.Fprintf(&b, "ttl: %s (%s)\n", info.TTL.Attribute, strings.ToLower(info.TTL.Status))
+	default:
+		b.WriteString("ttl: disabled\n")
+	}
+	return b.String()
+}
+
+// keyAttribute describes a key attribute by name and type.
+func keyAttribute(name, typ string) string {
+	return fmt.Sprintf("%s (%s)", name, typ)
+}
+
+// byteSize describes a number of bytes in the largest unit that keeps it above one, e.g. "4.1 KB".
+func byteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// maxTailLines is how many lines of a stream tail are kept.
+const maxTailLines = 500
+
+// eventColors maps each kind of stream record to the color it is drawn in.
+var eventColors = map[dynamodb.EventType]string{
+	dynamodb.Insert: "green",
+	dynamodb.Modify: "yellow",
+	dynamodb.Remove: "red",
+}
+
+// streamTail follows the stream of a table in the background.  Records arrive on records, and the result of the tail
+// on done once it stops.
+type streamTail struct {
+	table   string
+	lines   []string
+	records chan dynamodb.StreamRecord
+	done    chan error
+	cancel  context.CancelFunc
+}
+
+// startTail starts following the stream of table.
+func startTail(db dynamodb.Backend, table string) *streamTail {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &streamTail{
+		table:   table,
+		records: make(chan dynamodb.StreamRecord),
+		done:    make(chan error, 1),
+		cancel:  cancel,
+	}
+	go func() {
+		t.done <- db.TailStream(ctx, table, func(r dynamodb.StreamRecord) {
+			select {
+			case t.records <- r:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return t
+}
+
+// Stop following the stream.  Nothing more is sent on records.
+func (t *streamTail) Stop() {
+	t.cancel()
+}
+
+// Add a record to the tail, if its item matches the integration ID prefix and company filters.  Items without the
+// filtered attributes don't match.  Lines are cut to width.  False is returned if the record was filtered out.
+func (t *streamTail) Add(r dynamodb.StreamRecord, idPrefix, companyID string, width int) bool {
+	image := r.Image()
+	if idPrefix != "" && !strings.HasPrefix(stringAttr(image, dynamodb.IntegrationIDKey), idPrefix) {
+		return false
+	}
+	if companyID != "" && stringAttr(image, dynamodb.CompanyIDKey) != companyID {
+		return false
+	}
+
+	header := fmt.Sprintf("%s %s ", r.At.Format("15:04:05"), r.Event)
+	t.lines = append(t.lines, fmt.Sprintf("[%s](fg:%s)%s", header, eventColors[r.Event], cut(formatKey(r.Keys), width-len(header))))
+	if r.Event == dynamodb.Modify {
+		for _, d := range r.Diff() {
+			prefix := fmt.Sprintf("  %s %s: ", diffMarkers[d.Change], d.Name)
+			var values string
+			switch d.Change {
+			case dynamodb.Added:
+				values = attrText(d.New)
+			case dynamodb.Removed:
+				values = attrText(d.Old)
+			case dynamodb.Changed:
+				values = attrText(d.Old) + " -> " + attrText(d.New)
+			}
+			t.lines = append(t.lines, fmt.Sprintf("[%s](fg:%s)%s", prefix, diffColors[d.Change], cut(values, width-len(prefix))))
+		}
+	}
+	if len(t.lines) > maxTailLines {
+		t.lines = t.lines[len(t.lines)-maxTailLines:]
+	}
+	return true
+}
+
+// String returns the latest lines of the tail that fit in height.
+func (t *streamTail) String(height int) string {
+	if len(t.lines) == 0 {
+		return fmt.Sprintf("waiting for writes to %s...", t.table)
+	}
+	lines := t.lines
+	if len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stringAttr returns the value of a string attribute of item, or nothing if it has no such string attribute.
+func stringAttr(item dynamodb.Item, name string) string {
+	if av := item[name]; av != nil && av.S != nil {
+		return *av.S
+	}
+	return ""
+}
+
+// formatKey returns the JSON text of the key attributes of an item.
+func formatKey(key dynamodb.Item) string {
+	b, err := key.MarshalJSON()
+	if err != nil {
+		return "?"
+	}
+	return string(b)
+}
+
+// cut shortens s to width, marking where it was cut.
+func cut(s string, width int) string {
+	r := []rune(s)
+	if width < 1 || len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+// describeTelemetry returns the text of the telemetry panel, and the title and values, in milliseconds, of its
+// latency sparkline.
+func describeTelemetry(s dynamodb.TelemetrySnapshot) (string, string, []float64) {
+	if s.Requests == 0 {
+		return "no requests yet", "", nil
+	}
+
+	last := fmt.Sprintf("last: %s %.1f RCU %.1f WCU in %s", s.Last.Operation, s.Last.RCU, s.Last.WCU, roundLatency(s.Last.Latency))
+	if s.Last.Failed {
+		last += " (failed)"
+	}
+	session := fmt.Sprintf("session: %d requests, %.1f RCU, %.1f WCU", s.Requests, s.TotalRCU, s.TotalWCU)
+
+	var max time.Duration
+	values := make([]float64, 0, len(s.Latencies))
+	for _, l := range s.Latencies {
+		if l > max {
+			max = l
+		}
+		values = append(values, float64(l)/float64(time.Millisecond))
+	}
+	return last + "\n" + session, fmt.Sprintf("max %s", roundLatency(max)), values
+}
+
+// roundLatency rounds a latency to a readable precision.
+func roundLatency(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
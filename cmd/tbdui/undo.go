@@ -0,0 +1,42 @@
+package main
+
+import "github.com/swtch1/tbdui/dynamodb"
+
+// deletedItem is an item deleted during this session, along with the connection it was deleted through.
+type deletedItem struct {
+	conn  connection
+	table string
+	item  dynamodb.Item
+}
+
+// undoBuffer holds the items deleted during this session so they can be written back, most recent last.  Items are
+// kept across switches and restored through the connection they were deleted from.
+type undoBuffer struct {
+	deleted []deletedItem
+}
+
+// Push an item deleted from table through conn onto the buffer.
+func (b *undoBuffer) Push(conn connection, table string, item dynamodb.Item) {
+	b.deleted = append(b.deleted, deletedItem{conn: conn, table: table, item: item})
+}
+
+// Peek returns the most recently deleted item.  False is returned when the buffer is empty.
+func (b *undoBuffer) Peek() (deletedItem, bool) {
+	if len(b.deleted) == 0 {
+		return deletedItem{}, false
+	}
+	return b.deleted[len(b.deleted)-1], true
+}
+
+// Pop removes the most recently deleted item from the buffer.
+func (b *undoBuffer) Pop() {
+	if len(b.deleted) == 0 {
+		return
+	}
+	b.deleted = b.deleted[:len(b.deleted)-1]
+}
+
+// Len returns the number of items in the buffer.
+func (b *undoBuffer) Len() int {
+	return len(b.deleted)
+}
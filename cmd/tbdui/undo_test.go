@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/char"
+	"github.com/swtch1/tbdui/dynamodb"
+)
+
+func TestUndoBuffer(t *testing.T) {
+	t.Parallel()
+
+	dev := connection{environment: "dev"}
+	staging := connection{environment: "staging", region: "us-west-2"}
+
+	var b undoBuffer
+	_, ok := b.Peek()
+	require.False(t, ok)
+	b.Pop()
+	require.Equal(t, 0, b.Len())
+
+	b.Push(dev, "dev-integrations", dynamodb.Item{dynamodb.IntegrationIDKey: &ddb.AttributeValue{S: aws.String("int-1")}})
+	b.Push(staging, "staging-integrations", dynamodb.Item{dynamodb.IntegrationIDKey: &ddb.AttributeValue{S: aws.String("int-2")}})
+	require.Equal(t, 2, b.Len())
+
+	// the most recent delete comes back first, with the connection it was deleted through
+	d, ok := b.Peek()
+	require.True(t, ok)
+	require.Equal(t, deletedItem{conn: staging, table: "staging-integrations", item: dynamodb.Item{dynamodb.IntegrationIDKey: &ddb.AttributeValue{S: aws.String("int-2")}}}, d)
+	require.Equal(t, 2, b.Len(), "peeking keeps the item")
+
+	b.Pop()
+	d, ok = b.Peek()
+	require.True(t, ok)
+	require.Equal(t, deletedItem{conn: dev, table: "dev-integrations", item: dynamodb.Item{dynamodb.IntegrationIDKey: &ddb.AttributeValue{S: aws.String("int-1")}}}, d)
+
+	b.Pop()
+	_, ok = b.Peek()
+	require.False(t, ok)
+	require.Equal(t, 0, b.Len())
+}
+
+func TestRunUndoAfterSwitch(t *testing.T) {
+	t.Parallel()
+
+	db, err := dynamodb.NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	// staging has none of the dev tables, so restoring there fails
+	connect := func(environment, region string) (connection, error) {
+		return connection{db: dynamodb.NewMemoryDB(environment), environment: environment, region: region}, nil
+	}
+	r := runTUIWith(t, connection{db: db, environment: "dev"}, connect)
+	r.waitFor("dev-integrations")
+
+	r.typeText("int-slack")
+	r.press(char.ENTER)
+	r.waitFor(`"name": "Slack Alerts"`)
+	r.press(char.CTRL_D)
+	r.waitFor("Delete this item from dev-integrations?")
+	r.press("y")
+	r.waitFor("deleted item from dev-integrations")
+
+	r.press(char.CTRL_G)
+	r.waitFor(switchUsage)
+	r.press(char.BACKSPACE, char.BACKSPACE, char.BACKSPACE, char.BACKSPACE)
+	r.typeText("staging")
+	r.press(char.ENTER)
+	// the switch is done once the tables of staging have been loaded, and it has none
+	r.waitFor("table staging-integrations does not exist")
+
+	r.press(char.CTRL_Z)
+	r.waitFor("restored item in dev-integrations in dev")
+
+	schema, err := db.Schema(context.Background(), "dev-integrations")
+	require.NoError(t, err)
+	items, err := db.GetItems(context.Background(), schema, []dynamodb.Item{{dynamodb.IntegrationIDKey: &ddb.AttributeValue{S: aws.String("int-slack-001")}}})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "Slack Alerts", aws.StringValue(items[0]["name"].S))
+}
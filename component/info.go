@@ -41,8 +41,13 @@ func (i *Info) Dimensions() Dimensions {
 
 // Render registers the object's state with the UI.
 func (i *Info) Render() {
+	termui.Render(i.Drawables()...)
+}
+
+// Drawables returns the termui widgets which draw the component, ready to be rendered.
+func (i *Info) Drawables() []termui.Drawable {
 	i.preRender()
-	termui.Render(i.pg)
+	return []termui.Drawable{i.pg}
 }
 
 // preRender does all the work of translating the local component into the termui component before rendering.
@@ -1,6 +1,8 @@
 package component
 
 import (
+	"strings"
+
 	"github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
 	"github.com/swtch1/tbdui/char"
@@ -13,12 +15,16 @@ type InputBox struct {
 	pg        *widgets.Paragraph
 	blankText string
 	text      string
+	// cursorOffset is the number of runes between the cursor and the end of the text.
+	cursorOffset int
 
 	selected bool
 	// HideUnselectedText ensures no text is shown when the component is unselected. True by default.
 	HideUnselectedText bool
 	// AllowWrite from users.  True by default.
 	AllowWrite bool
+	// Mask hides the text behind asterisks, e.g. for secrets.  False by default.
+	Mask       bool
 	dimensions Dimensions
 
 	borderColor   termui.Color
@@ -50,6 +56,11 @@ func (b *InputBox) SetLogger(l *logger.UILogger) {
 	b.logger = l
 }
 
+// SetTitle replaces the title shown on the border of the component.
+func (b *InputBox) SetTitle(title string) {
+	b.pg.Title = title
+}
+
 // Widget returns the underlying termui widget.
 func (b *InputBox) Widget() *widgets.Paragraph {
 	b.preRender()
@@ -64,8 +75,13 @@ func (b *InputBox) Dimensions() Dimensions {
 
 // Render registers the object's state with the UI.
 func (b *InputBox) Render() {
+	termui.Render(b.Drawables()...)
+}
+
+// Drawables returns the termui widgets which draw the component, ready to be rendered.
+func (b *InputBox) Drawables() []termui.Drawable {
 	b.preRender()
-	termui.Render(b.pg)
+	return []termui.Drawable{b.pg}
 }
 
 // preRender does all the work of translating the local component into the termui component before rendering.
@@ -87,7 +103,40 @@ func (b *InputBox) preRender() {
 		b.pg.Text = ""
 		return
 	}
-	b.pg.Text = b.text
+
+	text := b.text
+	if b.Mask && b.text != b.blankText {
+		text = strings.Repeat(maskMarker, len([]rune(b.text)))
+	}
+
+	// show the cursor while typing
+	if b.selected && b.AllowWrite && b.text != b.blankText {
+		r := []rune(text)
+		pos := b.cursor()
+		b.pg.Text = string(r[:pos]) + cursorMarker + string(r[pos:])
+		return
+	}
+	b.pg.Text = text
+}
+
+// maskMarker is drawn in place of each character of masked text.
+const maskMarker = "*"
+
+// cursorMarker is drawn at the cursor position.
+const cursorMarker = "▏"
+
+// cursor returns the rune index of the cursor in the text.
+func (b *InputBox) cursor() int {
+	n := len([]rune(b.text))
+	if b.cursorOffset > n {
+		b.cursorOffset = n
+	}
+	return n - b.cursorOffset
+}
+
+// moveCursor moves the cursor to the rune index pos.
+func (b *InputBox) moveCursor(pos int) {
+	b.cursorOffset = len([]rune(b.text)) - pos
 }
 
 // Select marks the component as actively selected.
@@ -108,36 +157,101 @@ func (b *InputBox) Write(character string) {
 
 	if b.text == b.blankText {
 		b.text = ""
+		b.cursorOffset = 0
 	}
 
+	r := []rune(b.text)
+	pos := b.cursor()
 	switch character {
 	case char.SPACE:
-		b.text += " "
+		b.insert(" ")
 	case char.BACKSPACE:
-		if len(b.text) <= 1 {
+		if pos == 0 {
+			if len(r) == 0 {
+				b.Flush()
+			}
+			return
+		}
+		if len(r) <= 1 {
 			b.Flush()
 			return
 		}
-		b.text = b.text[0 : len(b.text)-1]
+		b.text = string(r[:pos-1]) + string(r[pos:])
+	case char.LEFT:
+		if pos > 0 {
+			b.moveCursor(pos - 1)
+		}
+	case char.RIGHT:
+		if pos < len(r) {
+			b.moveCursor(pos + 1)
+		}
+	case char.HOME:
+		b.moveCursor(lineStart(r, pos))
+	case char.END:
+		b.moveCursor(lineEnd(r, pos))
+	case char.UP:
+		start := lineStart(r, pos)
+		if start == 0 {
+			return
+		}
+		prevStart := lineStart(r, start-1)
+		b.moveCursor(min(prevStart+pos-start, start-1))
+	case char.DOWN:
+		start, end := lineStart(r, pos), lineEnd(r, pos)
+		if end == len(r) {
+			return
+		}
+		b.moveCursor(min(end+1+pos-start, lineEnd(r, end+1)))
 	default:
-		b.text += character
+		b.insert(character)
 
 	// no ops below
 	case char.TAB, char.ENTER:
-	case char.HOME, char.END, char.ESCAPE:
-	case char.UP, char.DOWN, char.LEFT, char.RIGHT:
+	case char.ESCAPE:
 	case char.NEXT, char.PREVIOUS:
 	}
 }
 
-// Overwrite any existing text in the component.
+// insert text at the cursor.
+func (b *InputBox) insert(text string) {
+	r := []rune(b.text)
+	pos := b.cursor()
+	b.text = string(r[:pos]) + text + string(r[pos:])
+}
+
+// lineStart returns the index of the first rune on the line containing pos.
+func lineStart(r []rune, pos int) int {
+	for pos > 0 && r[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+// lineEnd returns the index of the newline ending the line containing pos, or the end of the text.
+func lineEnd(r []rune, pos int) int {
+	for pos < len(r) && r[pos] != '\n' {
+		pos++
+	}
+	return pos
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Overwrite any existing text in the component.  The cursor is moved to the end of the text.
 func (b *InputBox) Overwrite(text string) {
 	b.text = text
+	b.cursorOffset = 0
 }
 
 // Flush all text in the component.
 func (b *InputBox) Flush() {
 	b.text = b.blankText
+	b.cursorOffset = 0
 }
 
 // Contents returns the contents of the component.  Empty box default text will never be returned.
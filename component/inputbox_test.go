@@ -86,3 +86,82 @@ func TestWritingToInputBox(t *testing.T) {
 		})
 	}
 }
+
+func TestInputBoxCursor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		initialText string
+		toWrite     []string
+		expected    string
+	}{
+		{
+			name:        "insert after moving left",
+			initialText: "fo",
+			toWrite:     []string{char.LEFT, "x"},
+			expected:    "fxo",
+		},
+		{
+			name:        "backspace after moving left",
+			initialText: "foo",
+			toWrite:     []string{char.LEFT, char.BACKSPACE},
+			expected:    "fo",
+		},
+		{
+			name:        "backspace at the start does nothing",
+			initialText: "foo",
+			toWrite:     []string{char.LEFT, char.LEFT, char.LEFT, char.LEFT, char.BACKSPACE},
+			expected:    "foo",
+		},
+		{
+			name:        "right stops at the end",
+			initialText: "foo",
+			toWrite:     []string{char.RIGHT, "x"},
+			expected:    "foox",
+		},
+		{
+			name:        "home and end stay on the line",
+			initialText: "ab\ncd",
+			toWrite:     []string{char.HOME, "x", char.END, "y"},
+			expected:    "ab\nxcdy",
+		},
+		{
+			name:        "up keeps the column",
+			initialText: "abc\nde",
+			toWrite:     []string{char.UP, "x"},
+			expected:    "abxc\nde",
+		},
+		{
+			name:        "down clamps to the shorter line",
+			initialText: "abc\nd",
+			toWrite:     []string{char.UP, char.END, char.DOWN, "x"},
+			expected:    "abc\ndx",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewInputBox("title", "", conf.Config{}, Dimensions{})
+			b.Overwrite(tt.initialText)
+			for _, c := range tt.toWrite {
+				b.Write(c)
+			}
+			require.Equal(t, tt.expected, b.text)
+		})
+	}
+}
+
+func TestInputBoxMask(t *testing.T) {
+	t.Parallel()
+
+	b := NewInputBox("", "", conf.NewDefault(), Dimensions{})
+	b.Mask = true
+	b.Overwrite("123456")
+	require.Equal(t, "******", b.Widget().Text)
+	require.Equal(t, "123456", b.Contents())
+
+	b.Select()
+	b.Write(char.LEFT)
+	require.Equal(t, "*****"+cursorMarker+"*", b.Widget().Text)
+}
@@ -1,8 +1,11 @@
 package component
 
 import (
+	"strings"
+
 	"github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
+	"github.com/swtch1/tbdui/char"
 	"github.com/swtch1/tbdui/conf"
 	"github.com/swtch1/tbdui/logger"
 )
@@ -10,6 +13,9 @@ import (
 // List records and displays input from a user.
 type List struct {
 	ls *widgets.List
+	// rows holds every row in the list, while the widget only holds the rows which match the filter.
+	rows   []string
+	filter string
 
 	selected   bool
 	dimensions Dimensions
@@ -39,7 +45,35 @@ func NewList(title string, c conf.Config, d Dimensions) *List {
 
 // AddRow to the list.
 func (l *List) AddRow(text string) {
-	l.ls.Rows = append(l.ls.Rows, text)
+	l.rows = append(l.rows, text)
+	l.applyFilter()
+}
+
+// Filter the visible rows down to those containing text.  An empty filter shows all rows.
+func (l *List) Filter(text string) {
+	l.filter = text
+	l.applyFilter()
+}
+
+// applyFilter rebuilds the visible rows from the current filter, keeping the selection in bounds.
+func (l *List) applyFilter() {
+	l.ls.Rows = []string{}
+	for _, r := range l.rows {
+		if strings.Contains(r, l.filter) {
+			l.ls.Rows = append(l.ls.Rows, r)
+		}
+	}
+	if l.ls.SelectedRow >= len(l.ls.Rows) {
+		l.ls.SelectedRow = 0
+	}
+}
+
+// Selected returns the text of the selected row, or an empty string if no rows are visible.
+func (l *List) Selected() string {
+	if len(l.ls.Rows) == 0 {
+		return ""
+	}
+	return l.ls.Rows[l.ls.SelectedRow]
 }
 
 // SetLogger for the component.
@@ -61,8 +95,13 @@ func (l *List) Dimensions() Dimensions {
 
 // Render registers the object's state with the UI.
 func (l *List) Render() {
+	termui.Render(l.Drawables()...)
+}
+
+// Drawables returns the termui widgets which draw the component, ready to be rendered.
+func (l *List) Drawables() []termui.Drawable {
 	l.preRender()
-	termui.Render(l.ls)
+	return []termui.Drawable{l.ls}
 }
 
 // preRender does all the work of translating the local component into the termui component before rendering.
@@ -111,10 +150,18 @@ func (l *List) Previous() int {
 	return l.ls.SelectedRow
 }
 
-// Write to a list exists to satisfy interfaces with other types, but here it is a no op.
-func (l *List) Write(character string) {}
+// Write to a list moves the selection with the arrow keys.  All other input is ignored.
+func (l *List) Write(character string) {
+	switch character {
+	case char.DOWN:
+		l.Next()
+	case char.UP:
+		l.Previous()
+	}
+}
 
-// Flush all text in the component.
+// Flush all rows in the component.
 func (l *List) Flush() {
-	l.ls.Rows = []string{}
+	l.rows = []string{}
+	l.applyFilter()
 }
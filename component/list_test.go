@@ -0,0 +1,86 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swtch1/tbdui/char"
+	"github.com/swtch1/tbdui/conf"
+)
+
+func TestFilteringList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		rows             []string
+		filter           string
+		moves            []string
+		expectedRows     []string
+		expectedSelected string
+	}{
+		{
+			name:             "no filter",
+			rows:             []string{"dev-integrations", "dev-mappings"},
+			expectedRows:     []string{"dev-integrations", "dev-mappings"},
+			expectedSelected: "dev-integrations",
+		},
+		{
+			name:             "filter narrows rows",
+			rows:             []string{"dev-integrations", "dev-mappings", "dev-integrations-archive"},
+			filter:           "integ",
+			expectedRows:     []string{"dev-integrations", "dev-integrations-archive"},
+			expectedSelected: "dev-integrations",
+		},
+		{
+			name:             "filter matches nothing",
+			rows:             []string{"dev-integrations"},
+			filter:           "nope",
+			expectedRows:     []string{},
+			expectedSelected: "",
+		},
+		{
+			name:             "selection follows arrow keys",
+			rows:             []string{"a", "b", "c"},
+			moves:            []string{char.DOWN, char.DOWN},
+			expectedRows:     []string{"a", "b", "c"},
+			expectedSelected: "c",
+		},
+		{
+			name:             "selection wraps",
+			rows:             []string{"a", "b", "c"},
+			moves:            []string{char.UP},
+			expectedRows:     []string{"a", "b", "c"},
+			expectedSelected: "c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewList("title", conf.Config{}, Dimensions{})
+			for _, r := range tt.rows {
+				l.AddRow(r)
+			}
+			l.Filter(tt.filter)
+			for _, m := range tt.moves {
+				l.Write(m)
+			}
+			require.Equal(t, tt.expectedRows, l.ls.Rows)
+			require.Equal(t, tt.expectedSelected, l.Selected())
+		})
+	}
+}
+
+func TestFilterKeepsSelectionInBounds(t *testing.T) {
+	t.Parallel()
+
+	l := NewList("title", conf.Config{}, Dimensions{})
+	for _, r := range []string{"aa", "ab", "b"} {
+		l.AddRow(r)
+	}
+	l.Write(char.UP)
+	require.Equal(t, "b", l.Selected())
+
+	l.Filter("a")
+	require.Equal(t, "aa", l.Selected())
+}
@@ -0,0 +1,70 @@
+package component
+
+import (
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/swtch1/tbdui/conf"
+)
+
+// Modal is a dialog drawn over other components.  It is only rendered while shown, so it should be rendered after
+// the components it covers.
+type Modal struct {
+	pg      *widgets.Paragraph
+	visible bool
+
+	dimensions Dimensions
+}
+
+// NewModal initializes a hidden modal.
+func NewModal(title string, c conf.Config, d Dimensions) *Modal {
+	p := widgets.NewParagraph()
+	p.Title = title
+	p.SetRect(d.X1, d.Y1, d.X2, d.Y2)
+	p.BorderStyle.Fg = c.DefaultSecondaryColor
+	return &Modal{
+		pg:         p,
+		dimensions: d,
+	}
+}
+
+// Widget returns the underlying termui widget.
+func (m *Modal) Widget() *widgets.Paragraph {
+	return m.pg
+}
+
+// Dimensions returns the current dimensions of the component.
+func (m *Modal) Dimensions() Dimensions {
+	return m.dimensions
+}
+
+// Show the modal with the given text.
+func (m *Modal) Show(text string) {
+	m.pg.Text = text
+	m.visible = true
+}
+
+// Hide the modal.
+func (m *Modal) Hide() {
+	m.visible = false
+}
+
+// Visible returns true while the modal is shown.
+func (m *Modal) Visible() bool {
+	return m.visible
+}
+
+// Render registers the object's state with the UI, if it is shown.
+func (m *Modal) Render() {
+	if !m.visible {
+		return
+	}
+	termui.Render(m.Drawables()...)
+}
+
+// Drawables returns the termui widgets which draw the component, or none while it is hidden.
+func (m *Modal) Drawables() []termui.Drawable {
+	if !m.visible {
+		return nil
+	}
+	return []termui.Drawable{m.pg}
+}
@@ -0,0 +1,55 @@
+package component
+
+import (
+	"github.com/gizak/termui/v3"
+	"github.com/swtch1/tbdui/conf"
+)
+
+// Prompt is a single line of input drawn over other components.  It is only rendered while shown, so it should be
+// rendered after the components it covers.
+type Prompt struct {
+	*InputBox
+	visible bool
+}
+
+// NewPrompt initializes a hidden prompt.
+func NewPrompt(c conf.Config, d Dimensions) *Prompt {
+	b := NewInputBox("", "", c, d)
+	b.HideUnselectedText = false
+	return &Prompt{InputBox: b}
+}
+
+// Show the prompt with a title and some starting text, ready for input.
+func (p *Prompt) Show(title, text string) {
+	p.SetTitle(title)
+	p.Overwrite(text)
+	p.Select()
+	p.visible = true
+}
+
+// Hide the prompt.
+func (p *Prompt) Hide() {
+	p.Deselect()
+	p.visible = false
+}
+
+// Visible returns true while the prompt is shown.
+func (p *Prompt) Visible() bool {
+	return p.visible
+}
+
+// Render registers the object's state with the UI, if it is shown.
+func (p *Prompt) Render() {
+	if !p.visible {
+		return
+	}
+	p.InputBox.Render()
+}
+
+// Drawables returns the termui widgets which draw the prompt, or none while it is hidden.
+func (p *Prompt) Drawables() []termui.Drawable {
+	if !p.visible {
+		return nil
+	}
+	return p.InputBox.Drawables()
+}
@@ -0,0 +1,66 @@
+package component
+
+import (
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/swtch1/tbdui/conf"
+)
+
+// Telemetry is a read-only panel of request costs, with a sparkline of recent latencies beside them.
+type Telemetry struct {
+	pg    *widgets.Paragraph
+	line  *widgets.Sparkline
+	group *widgets.SparklineGroup
+
+	dimensions Dimensions
+}
+
+// NewTelemetry initializes an empty telemetry panel.  The text takes the left half and the sparkline the right.
+func NewTelemetry(title string, c conf.Config, d Dimensions) *Telemetry {
+	mid := d.X1 + (d.X2-d.X1)/2
+
+	p := widgets.NewParagraph()
+	p.Title = title
+	p.SetRect(d.X1, d.Y1, mid, d.Y2)
+	p.BorderStyle.Fg = c.DefaultPrimaryColor
+
+	l := widgets.NewSparkline()
+	l.LineColor = c.DefaultSecondaryColor
+	g := widgets.NewSparklineGroup(l)
+	g.Title = "latency"
+	g.SetRect(mid, d.Y1, d.X2, d.Y2)
+	g.BorderStyle.Fg = c.DefaultPrimaryColor
+
+	return &Telemetry{
+		pg:         p,
+		line:       l,
+		group:      g,
+		dimensions: d,
+	}
+}
+
+// Dimensions returns the current dimensions of the component.
+func (t *Telemetry) Dimensions() Dimensions {
+	return t.dimensions
+}
+
+// Set the text of the panel, and the values drawn in the sparkline, oldest first.  Only the newest values which fit
+// in the sparkline are drawn.
+func (t *Telemetry) Set(text, sparkTitle string, values []float64) {
+	t.pg.Text = text
+	t.line.Title = sparkTitle
+	if w := t.group.Inner.Dx(); len(values) > w {
+		values = values[len(values)-w:]
+	}
+	t.line.Data = values
+}
+
+// Render registers the object's state with the UI.
+func (t *Telemetry) Render() {
+	termui.Render(t.Drawables()...)
+}
+
+// Drawables returns the termui widgets which draw the component, ready to be rendered.
+func (t *Telemetry) Drawables() []termui.Drawable {
+	return []termui.Drawable{t.pg, t.group}
+}
@@ -1,10 +1,71 @@
 package conf
 
-import "github.com/gizak/termui/v3"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gizak/termui/v3"
+)
 
 type Config struct {
-	DefaultPrimaryColor   termui.Color
-	DefaultSecondaryColor termui.Color
+	DefaultPrimaryColor   termui.Color `json:"-"`
+	DefaultSecondaryColor termui.Color `json:"-"`
+
+	// Endpoint overrides the DynamoDB endpoint, e.g. to browse DynamoDB Local.
+	Endpoint string `json:"endpoint"`
+	// STSEndpoint overrides the STS endpoint roles are assumed through, e.g. a VPC endpoint.
+	STSEndpoint string `json:"sts_endpoint"`
+	// Profile is the named AWS profile used for credentials and region.  AWS_PROFILE is used when it is empty.
+	Profile string `json:"profile"`
+	// ScanWorkers is the number of segments full table scans are split into, and scanned in parallel.
+	ScanWorkers int `json:"scan_workers"`
+	// CacheTTL is how long read results are reused before they are fetched again.  Zero disables the cache.
+	CacheTTL Duration `json:"cache_ttl"`
+	// Environments holds settings for individual environments, by name.
+	Environments map[string]Environment `json:"environments"`
+}
+
+// Environment holds the settings for a single environment.
+type Environment struct {
+	// Profile overrides the top level profile for this environment.
+	Profile string `json:"profile"`
+	// RoleARN is assumed to access this environment, if set.
+	RoleARN string `json:"role_arn"`
+	// MFASerial is the MFA device required to assume RoleARN, if any.  The token code is prompted for in the UI.
+	MFASerial string `json:"mfa_serial"`
+}
+
+// Environment returns the settings for the named environment, with the top level profile applied.
+func (c Config) Environment(name string) Environment {
+	e := c.Environments[name]
+	if e.Profile == "" {
+		e.Profile = c.Profile
+	}
+	return e
+}
+
+// Duration is a time.Duration written as a string in JSON, e.g. "5m".
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5m\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON writes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
 }
 
 // NewDefault initializes a new default configuration.
@@ -12,5 +73,25 @@ func NewDefault() Config {
 	return Config{
 		DefaultPrimaryColor:   termui.ColorGreen,
 		DefaultSecondaryColor: termui.ColorCyan,
+		ScanWorkers:           4,
+		CacheTTL:              Duration(5 * time.Minute),
+	}
+}
+
+// Load the JSON configuration file at path over the defaults.  A missing file is not an error.
+func Load(path string) (Config, error) {
+	c := NewDefault()
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to open config: %w", err)
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&c); err != nil {
+		return c, fmt.Errorf("failed to read config %s: %w", path, err)
 	}
+	return c, nil
 }
@@ -0,0 +1,94 @@
+package conf
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfig writes contents to a config file which is removed when the test ends, returning its path.
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "tbdui-conf")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfig(t, `{
+		"endpoint": "http://localhost:8000",
+		"sts_endpoint": "https://sts.us-east-1.amazonaws.com",
+		"profile": "work",
+		"cache_ttl": "30s",
+		"environments": {
+			"prod": {"profile": "prod", "role_arn": "arn:aws:iam::123456789012:role/prod", "mfa_serial": "arn:aws:iam::123456789012:mfa/someone"},
+			"staging": {"role_arn": "arn:aws:iam::123456789012:role/staging"}
+		}
+	}`)
+	c, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost:8000", c.Endpoint)
+	require.Equal(t, "https://sts.us-east-1.amazonaws.com", c.STSEndpoint)
+	require.Equal(t, Duration(30*time.Second), c.CacheTTL)
+	// settings missing from the file keep their defaults
+	require.Equal(t, NewDefault().ScanWorkers, c.ScanWorkers)
+
+	require.Equal(t, Environment{
+		Profile:   "prod",
+		RoleARN:   "arn:aws:iam::123456789012:role/prod",
+		MFASerial: "arn:aws:iam::123456789012:mfa/someone",
+	}, c.Environment("prod"))
+	// environments without a profile use the top level one
+	require.Equal(t, Environment{Profile: "work", RoleARN: "arn:aws:iam::123456789012:role/staging"}, c.Environment("staging"))
+	require.Equal(t, Environment{Profile: "work"}, c.Environment("dev"))
+}
+
+func TestLoadMissing(t *testing.T) {
+	t.Parallel()
+
+	c, err := Load(filepath.Join(os.TempDir(), "tbdui-conf-missing", "config.json"))
+	require.NoError(t, err)
+	require.Equal(t, NewDefault(), c)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{name: "not JSON", contents: `endpoint = "http://localhost:8000"`},
+		{name: "duration without a unit", contents: `{"cache_ttl": "30"}`},
+		{name: "duration as a number", contents: `{"cache_ttl": 30}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.contents))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	t.Parallel()
+
+	b, err := json.Marshal(Duration(90 * time.Second))
+	require.NoError(t, err)
+	require.Equal(t, `"1m30s"`, string(b))
+
+	var d Duration
+	require.NoError(t, json.Unmarshal(b, &d))
+	require.Equal(t, Duration(90*time.Second), d)
+}
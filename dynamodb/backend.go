@@ -0,0 +1,44 @@
+package dynamodb
+
+import (
+	"context"
+
+	"github.com/swtch1/tbdui/logger"
+)
+
+// Backend is everything the TUI needs from a DynamoDB store.  DB talks to AWS and MemoryDB holds everything in
+// memory, for running offline.  Either can be wrapped in a Cache.
+type Backend interface {
+	SetLogger(l *logger.UILogger)
+
+	Tables(ctx context.Context) ([]string, error)
+	SetTable(name string)
+	Table() string
+	Schema(ctx context.Context, table string) (TableSchema, error)
+	TimeToLive(ctx context.Context, table string) (TimeToLive, error)
+	Describe(ctx context.Context, table string) (TableInfo, error)
+	Query(ctx context.Context, q KeyQuery) ([]Item, error)
+	Scan(ctx context.Context, table string, projection Projection) ([]Item, error)
+	ParallelScan(ctx context.Context, table string, segments int, projection Projection) ([]Item, error)
+	Sample(ctx context.Context, table string, limit int) ([]Item, error)
+	ExecuteStatement(ctx context.Context, statement, nextToken string) (StatementPage, error)
+	ReplaceItem(ctx context.Context, schema TableSchema, original, updated Item) (Item, error)
+	DeleteItem(ctx context.Context, schema TableSchema, item Item) (Item, error)
+	CreateItem(ctx context.Context, schema TableSchema, item Item) error
+	GetItems(ctx context.Context, schema TableSchema, keys []Item) ([]Item, error)
+	PutItems(ctx context.Context, table string, items []Item) (int, error)
+	Transact(ctx context.Context, changes []StagedChange) error
+	TailStream(ctx context.Context, table string, handle func(StreamRecord)) error
+
+	AllIntegrations(ctx context.Context) ([]Integration, error)
+	Integration(ctx context.Context, id string) (Integration, error)
+	MatchingIntegrationIDs(ctx context.Context, prefix string) ([]string, error)
+	CompanyIntegrations(ctx context.Context, companyID string) ([]Integration, error)
+	SearchIntegrations(ctx context.Context, idPrefix, companyID string, projection Projection) ([]Integration, error)
+}
+
+var (
+	_ Backend = (*DB)(nil)
+	_ Backend = (*MemoryDB)(nil)
+	_ Backend = (*Cache)(nil)
+)
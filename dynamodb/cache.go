@@ -0,0 +1,326 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache is a Backend which remembers the results of reads for a while, so repeating a search doesn't repeat the
+// requests.  Results are cached by table, operation and parameters.  Writes through the cache forget everything
+// cached for the table written to, but writes made elsewhere are only seen once results expire or are refreshed.
+type Cache struct {
+	Backend
+	ttl time.Duration
+	now func() time.Time
+
+	lock sync.Mutex
+	// entries are the cached results of each table, by operation and parameters.
+	entries map[string]map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value interface{}
+	at    time.Time
+}
+
+// NewCache caches the reads of b for ttl.
+func NewCache(b Backend, ttl time.Duration) *Cache {
+	return &Cache{
+		Backend: b,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]map[string]cacheEntry),
+	}
+}
+
+// CacheControl decides how calls made with a context use the cache, and records how they did.
+type CacheControl struct {
+	refresh bool
+
+	lock     sync.Mutex
+	cachedAt time.Time
+}
+
+type cacheControlKey struct{}
+
+// WithCacheControl returns a context whose calls skip cached results when refresh is set.  The results they get
+// are cached either way.
+func WithCacheControl(ctx context.Context, refresh bool) (context.Context, *CacheControl) {
+	c := &CacheControl{refresh: refresh}
+	return context.WithValue(ctx, cacheControlKey{}, c), c
+}
+
+// CachedAt returns when the oldest cached result used by calls with the context was read.  False is returned when
+// every result was read fresh.
+func (c *CacheControl) CachedAt() (time.Time, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.cachedAt, !c.cachedAt.IsZero()
+}
+
+// hit records the use of a result read at t.
+func (c *CacheControl) hit(t time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cachedAt.IsZero() || t.Before(c.cachedAt) {
+		c.cachedAt = t
+	}
+}
+
+// cached returns the cached result of an operation on a table, or loads and caches it.  Results are copied going in
+// and coming out, so callers are free to change them.
+func (c *Cache) cached(ctx context.Context, table, op string, params interface{}, clone func(interface{}) interface{}, load func() (interface{}, error)) (interface{}, error) {
+	key := fmt.Sprintf("%s %#v", op, params)
+	control, _ := ctx.Value(cacheControlKey{}).(*CacheControl)
+
+	if control == nil || !control.refresh {
+		c.lock.Lock()
+		e, ok := c.entries[table][key]
+		c.lock.Unlock()
+		if ok && c.now().Sub(e.at) < c.ttl {
+			if control != nil {
+				control.hit(e.at)
+			}
+			return clone(e.value), nil
+		}
+	}
+
+	at := c.now()
+	v, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.entries[table] == nil {
+		c.entries[table] = make(map[string]cacheEntry)
+	}
+	c.entries[table][key] = cacheEntry{value: clone(v), at: at}
+	return v, nil
+}
+
+// forget everything cached for a table.
+func (c *Cache) forget(table string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, table)
+}
+
+// forgetAll cached results.
+func (c *Cache) forgetAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = make(map[string]map[string]cacheEntry)
+}
+
+func copyItems(v interface{}) interface{} {
+	items := v.([]Item)
+	if items == nil {
+		return items
+	}
+	c := make([]Item, len(items))
+	for i := range items {
+		c[i] = items[i].Copy()
+	}
+	return c
+}
+
+func copyIntegration(v interface{}) interface{} {
+	i := v.(Integration)
+	if i.item != nil {
+		i.item = i.item.Copy()
+	}
+	return i
+}
+
+func copyIntegrations(v interface{}) interface{} {
+	integrations := v.([]Integration)
+	if integrations == nil {
+		return integrations
+	}
+	c := make([]Integration, len(integrations))
+	for i := range integrations {
+		c[i] = copyIntegration(integrations[i]).(Integration)
+	}
+	return c
+}
+
+func copyStrings(v interface{}) interface{} {
+	return append([]string(nil), v.([]string)...)
+}
+
+func copyTableInfo(v interface{}) interface{} {
+	info := v.(TableInfo)
+	indexes := info.Indexes
+	info.Indexes = nil
+	for _, i := range indexes {
+		i.ProjectedAttributes = append([]string(nil), i.ProjectedAttributes...)
+		info.Indexes = append(info.Indexes, i)
+	}
+	capacity := info.IndexCapacity
+	info.IndexCapacity = make(map[string]Capacity, len(capacity))
+	for name, c := range capacity {
+		info.IndexCapacity[name] = c
+	}
+	return info
+}
+
+// Query returns the cached result of the query, or runs it.
+func (c *Cache) Query(ctx context.Context, q KeyQuery) ([]Item, error) {
+	v, err := c.cached(ctx, q.Table, "query", q, copyItems, func() (interface{}, error) {
+		return c.Backend.Query(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]Item), nil
+}
+
+// Scan returns the cached items of a table, or scans it.
+func (c *Cache) Scan(ctx context.Context, table string, projection Projection) ([]Item, error) {
+	v, err := c.cached(ctx, table, "scan", projection, copyItems, func() (interface{}, error) {
+		return c.Backend.Scan(ctx, table, projection)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]Item), nil
+}
+
+// ParallelScan returns the cached items of a table, or scans it.  Scans share results however many segments they use.
+func (c *Cache) ParallelScan(ctx context.Context, table string, segments int, projection Projection) ([]Item, error) {
+	v, err := c.cached(ctx, table, "scan", projection, copyItems, func() (interface{}, error) {
+		return c.Backend.ParallelScan(ctx, table, segments, projection)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]Item), nil
+}
+
+// Describe returns the cached description of a table, or reads it.
+func (c *Cache) Describe(ctx context.Context, table string) (TableInfo, error) {
+	v, err := c.cached(ctx, table, "describe", nil, copyTableInfo, func() (interface{}, error) {
+		return c.Backend.Describe(ctx, table)
+	})
+	if err != nil {
+		return TableInfo{}, err
+	}
+	return v.(TableInfo), nil
+}
+
+// Sample returns the cached sample of a table, or reads it.
+func (c *Cache) Sample(ctx context.Context, table string, limit int) ([]Item, error) {
+	v, err := c.cached(ctx, table, "sample", limit, copyItems, func() (interface{}, error) {
+		return c.Backend.Sample(ctx, table, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]Item), nil
+}
+
+// AllIntegrations returns the cached integrations of the target table, or reads them.
+func (c *Cache) AllIntegrations(ctx context.Context) ([]Integration, error) {
+	v, err := c.cached(ctx, c.Table(), "all integrations", nil, copyIntegrations, func() (interface{}, error) {
+		return c.Backend.AllIntegrations(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]Integration), nil
+}
+
+// Integration returns a cached integration from the target table, or reads it.
+func (c *Cache) Integration(ctx context.Context, id string) (Integration, error) {
+	v, err := c.cached(ctx, c.Table(), "integration", id, copyIntegration, func() (interface{}, error) {
+		return c.Backend.Integration(ctx, id)
+	})
+	if err != nil {
+		return Integration{}, err
+	}
+	return v.(Integration), nil
+}
+
+// MatchingIntegrationIDs returns cached matching IDs from the target table, or reads them.
+func (c *Cache) MatchingIntegrationIDs(ctx context.Context, prefix string) ([]string, error) {
+	v, err := c.cached(ctx, c.Table(), "matching integration ids", prefix, copyStrings, func() (interface{}, error) {
+		return c.Backend.MatchingIntegrationIDs(ctx, prefix)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]string), nil
+}
+
+// CompanyIntegrations returns the cached integrations of a company in the target table, or reads them.
+func (c *Cache) CompanyIntegrations(ctx context.Context, companyID string) ([]Integration, error) {
+	v, err := c.cached(ctx, c.Table(), "company integrations", companyID, copyIntegrations, func() (interface{}, error) {
+		return c.Backend.CompanyIntegrations(ctx, companyID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]Integration), nil
+}
+
+// SearchIntegrations returns cached matching integrations from the target table, or searches for them.
+func (c *Cache) SearchIntegrations(ctx context.Context, idPrefix, companyID string, projection Projection) ([]Integration, error) {
+	params := struct {
+		idPrefix, companyID string
+		projection          Projection
+	}{idPrefix, companyID, projection}
+	v, err := c.cached(ctx, c.Table(), "search integrations", params, copyIntegrations, func() (interface{}, error) {
+		return c.Backend.SearchIntegrations(ctx, idPrefix, companyID, projection)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]Integration), nil
+}
+
+// ExecuteStatement runs a PartiQL statement, which is never cached.  Anything but a SELECT may write, and the table
+// isn't known, so everything cached is forgotten.
+func (c *Cache) ExecuteStatement(ctx context.Context, statement, nextToken string) (StatementPage, error) {
+	if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(statement)), "SELECT") {
+		defer c.forgetAll()
+	}
+	return c.Backend.ExecuteStatement(ctx, statement, nextToken)
+}
+
+// ReplaceItem replaces an item and forgets the cached results of its table.
+func (c *Cache) ReplaceItem(ctx context.Context, schema TableSchema, original, updated Item) (Item, error) {
+	defer c.forget(schema.Name)
+	return c.Backend.ReplaceItem(ctx, schema, original, updated)
+}
+
+// DeleteItem deletes an item and forgets the cached results of its table.
+func (c *Cache) DeleteItem(ctx context.Context, schema TableSchema, item Item) (Item, error) {
+	defer c.forget(schema.Name)
+	return c.Backend.DeleteItem(ctx, schema, item)
+}
+
+// CreateItem creates an item and forgets the cached results of its table.
+func (c *Cache) CreateItem(ctx context.Context, schema TableSchema, item Item) error {
+	defer c.forget(schema.Name)
+	return c.Backend.CreateItem(ctx, schema, item)
+}
+
+// PutItems writes items and forgets the cached results of their table.
+func (c *Cache) PutItems(ctx context.Context, table string, items []Item) (int, error) {
+	defer c.forget(table)
+	return c.Backend.PutItems(ctx, table, items)
+}
+
+// Transact commits changes and forgets the cached results of every table they touch.
+func (c *Cache) Transact(ctx context.Context, changes []StagedChange) error {
+	defer func() {
+		for _, change := range changes {
+			c.forget(change.Schema.Name)
+		}
+	}()
+	return c.Backend.Transact(ctx, changes)
+}
@@ -0,0 +1,66 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	cache := NewCache(db, time.Minute)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	schema, err := db.Schema(context.Background(), db.Table())
+	require.NoError(t, err)
+
+	search := func(refresh bool) ([]Integration, time.Time, bool) {
+		ctx, control := WithCacheControl(context.Background(), refresh)
+		integrations, err := cache.SearchIntegrations(ctx, "int-s", "", nil)
+		require.NoError(t, err)
+		at, cached := control.CachedAt()
+		return integrations, at, cached
+	}
+
+	first, _, cached := search(false)
+	require.False(t, cached)
+	require.Len(t, first, 3)
+
+	// changes made behind the cache's back aren't seen until they are refreshed
+	item, err := DecodeItem([]byte(`{"integration_id": "int-sap-001"}`))
+	require.NoError(t, err)
+	require.NoError(t, db.CreateItem(context.Background(), schema, item))
+	now = now.Add(30 * time.Second)
+	second, at, cached := search(false)
+	require.True(t, cached)
+	require.Equal(t, now.Add(-30*time.Second), at)
+	require.Len(t, second, 3)
+
+	refreshed, _, cached := search(true)
+	require.False(t, cached)
+	require.Len(t, refreshed, 4)
+
+	// results expire
+	now = now.Add(2 * time.Minute)
+	_, _, cached = search(false)
+	require.False(t, cached)
+
+	// writes through the cache are seen at once
+	_, _, cached = search(false)
+	require.True(t, cached)
+	_, err = cache.DeleteItem(context.Background(), schema, item)
+	require.NoError(t, err)
+	afterDelete, _, cached := search(false)
+	require.False(t, cached)
+	require.Len(t, afterDelete, 3)
+
+	// cached results can be changed by the caller
+	afterDelete[0].Name = "changed"
+	again, _, _ := search(false)
+	require.NotEqual(t, "changed", again[0].Name)
+}
@@ -0,0 +1,72 @@
+package dynamodb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Override replaces a single attribute of an item, written as "name=value".
+type Override struct {
+	Name  string
+	Value string
+}
+
+// ParseOverride reads an override written as "name=value".
+func ParseOverride(s string) (Override, error) {
+	i := strings.Index(s, "=")
+	if i < 1 {
+		return Override{}, fmt.Errorf("expected name=value, got %q", s)
+	}
+	return Override{Name: s[:i], Value: s[i+1:]}, nil
+}
+
+// CloneItem returns a copy of item with overrides applied, checking the result has every attribute of key.
+//
+// Key attributes are converted to their key type, and attributes which are currently strings stay strings, so that
+// rewriting an ID like "123" doesn't turn it into a number.  Any other value is read as JSON when it is valid JSON,
+// and as a string otherwise.
+func CloneItem(key KeySchema, item Item, overrides []Override) (Item, error) {
+	clone := item.Copy()
+	for _, o := range overrides {
+		av, err := overrideValue(key, clone[o.Name], o)
+		if err != nil {
+			return nil, fmt.Errorf("override %q: %w", o.Name, err)
+		}
+		clone[o.Name] = av
+	}
+
+	for _, name := range []string{key.HashKey, key.RangeKey} {
+		if name == "" {
+			continue
+		}
+		if _, ok := clone[name]; !ok {
+			return nil, fmt.Errorf("clone is missing key attribute %q", name)
+		}
+	}
+	return clone, nil
+}
+
+// overrideValue converts an override into an attribute value, given the attribute it replaces, if any.
+func overrideValue(key KeySchema, current *ddb.AttributeValue, o Override) (*ddb.AttributeValue, error) {
+	switch {
+	case o.Name == key.HashKey:
+		return keyValue(key.HashKeyType, o.Value)
+	case o.Name == key.RangeKey:
+		return keyValue(key.RangeKeyType, o.Value)
+	case current != nil && current.S != nil:
+		return &ddb.AttributeValue{S: aws.String(o.Value)}, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(o.Value)))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return &ddb.AttributeValue{S: aws.String(o.Value)}, nil
+	}
+	return attrFromJSON(v)
+}
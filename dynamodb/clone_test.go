@@ -0,0 +1,76 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneItem(t *testing.T) {
+	t.Parallel()
+
+	key := KeySchema{HashKey: "integration_id", HashKeyType: "S"}
+	original := `{"integration_id": "int-1", "company_id": "acme", "enabled": true, "version": 3}`
+
+	tests := []struct {
+		name      string
+		overrides []string
+		expected  string
+	}{
+		{
+			name:     "no overrides",
+			expected: original,
+		},
+		{
+			name:      "rewrite keys",
+			overrides: []string{"integration_id=int-2", "company_id=globex"},
+			expected:  `{"integration_id": "int-2", "company_id": "globex", "enabled": true, "version": 3}`,
+		},
+		{
+			name:      "strings stay strings",
+			overrides: []string{"integration_id=123", "company_id=456"},
+			expected:  `{"integration_id": "123", "company_id": "456", "enabled": true, "version": 3}`,
+		},
+		{
+			name:      "JSON values",
+			overrides: []string{"enabled=false", "version=1", `config={"sandbox": true}`},
+			expected:  `{"integration_id": "int-1", "company_id": "acme", "enabled": false, "version": 1, "config": {"sandbox": true}}`,
+		},
+		{
+			name:      "plain text for new attributes",
+			overrides: []string{"note=copied from dev"},
+			expected:  `{"integration_id": "int-1", "company_id": "acme", "enabled": true, "version": 3, "note": "copied from dev"}`,
+		},
+		{
+			name:      "empty key",
+			overrides: []string{"integration_id=", "company_id=acme"},
+			expected:  `{"integration_id": "", "company_id": "acme", "enabled": true, "version": 3}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := DecodeItem([]byte(original))
+			require.NoError(t, err)
+
+			var overrides []Override
+			for _, s := range tt.overrides {
+				o, err := ParseOverride(s)
+				require.NoError(t, err)
+				overrides = append(overrides, o)
+			}
+
+			clone, err := CloneItem(key, item, overrides)
+			require.NoError(t, err)
+			expected, err := DecodeItem([]byte(tt.expected))
+			require.NoError(t, err)
+			require.Equal(t, expected, clone)
+		})
+	}
+
+	// the clone must keep its keys
+	_, err := CloneItem(KeySchema{HashKey: "id", HashKeyType: "S"}, Item{}, nil)
+	require.Error(t, err)
+	_, err = ParseOverride("=value")
+	require.Error(t, err)
+}
@@ -1,36 +1,169 @@
 package dynamodb
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/guregu/dynamo"
 	"github.com/swtch1/tbdui/logger"
 )
 
 // DB is a DynamoDB instance.
 type DB struct {
-	dynDB       *dynamo.DB
+	dynDB *dynamo.DB
+	// streams reads the streams of tables.
+	streams     *dynamodbstreams.DynamoDBStreams
 	Environment string
-	logger      *logger.UILogger
+	// Region the DB connects to, resolved from the AWS config when it isn't given.
+	Region string
+	// table is the target of searches.  The environment's integrations table is used when it is empty.
+	table  string
+	logger *logger.UILogger
 }
 
-// NewDB instantiates a new Dynamo DB.
-func NewDB(awsCredentials *credentials.Credentials, awsRegion, environment string) (*DB, error) {
-	sess, err := session.NewSession(&aws.Config{Credentials: awsCredentials})
+// Config configures a connection to DynamoDB.
+type Config struct {
+	// Credentials replace the default AWS credential chain when set.
+	Credentials *credentials.Credentials
+	// Profile is a named profile from the shared AWS config files.  AWS_PROFILE, or the default profile, is used when
+	// it is empty.
+	Profile string
+	// RoleARN is assumed on top of the base credentials when set.
+	RoleARN string
+	// MFASerial identifies the MFA device required to assume RoleARN, if any.
+	MFASerial string
+	// TokenProvider is asked for an MFA token code whenever assuming a role requires one.
+	TokenProvider func() (string, error)
+	// Region is resolved from the environment and the shared AWS config files when it is empty.
+	Region string
+	// DefaultRegion is used when Region is empty and no region is configured for AWS.
+	DefaultRegion string
+	Environment   string
+	// Endpoint overrides the DynamoDB endpoint, e.g. to connect to DynamoDB Local.  The AWS endpoint for the region
+	// is used when it is empty.
+	Endpoint string
+	// STSEndpoint overrides the STS endpoint RoleARN is assumed through, e.g. a VPC endpoint.  The AWS endpoint for the
+	// region is used when it is empty.
+	STSEndpoint string
+	// Telemetry records the cost of every request, when set.
+	Telemetry *Telemetry
+}
+
+// NewDB instantiates a new Dynamo DB.  Credentials are not retrieved until the first request is made.
+func NewDB(c Config) (*DB, error) {
+	awsConfig := aws.Config{Credentials: c.Credentials}
+	if c.Region != "" {
+		awsConfig.Region = aws.String(c.Region)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:                  awsConfig,
+		Profile:                 c.Profile,
+		SharedConfigState:       session.SharedConfigEnable,
+		AssumeRoleTokenProvider: c.TokenProvider,
+	})
 	if err != nil {
 		return &DB{}, fmt.Errorf("error creating new AWS session: %w", err)
 	}
+	if aws.StringValue(sess.Config.Region) == "" && c.DefaultRegion != "" {
+		sess = sess.Copy(&aws.Config{Region: aws.String(c.DefaultRegion)})
+	}
+	if aws.StringValue(sess.Config.Region) == "" {
+		return &DB{}, fmt.Errorf("no AWS region is configured, set AWS_REGION or a region in the AWS profile")
+	}
 
-	db := dynamo.New(
-		sess,
-		&aws.Config{Region: aws.String(awsRegion)},
-	)
+	dbConfig := &aws.Config{}
+	if c.RoleARN != "" {
+		stsConfig := &aws.Config{}
+		if c.STSEndpoint != "" {
+			stsConfig.Endpoint = aws.String(c.STSEndpoint)
+		}
+		dbConfig.Credentials = stscreds.NewCredentialsWithClient(sts.New(sess, stsConfig), c.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+			if c.MFASerial != "" {
+				p.SerialNumber = aws.String(c.MFASerial)
+				p.TokenProvider = c.TokenProvider
+			}
+		})
+	}
+	if c.Endpoint != "" {
+		dbConfig.Endpoint = aws.String(c.Endpoint)
+	}
+	client := ddb.New(sess, dbConfig)
+	instrumentRetries(&client.Handlers)
+	if c.Telemetry != nil {
+		c.Telemetry.instrument(&client.Handlers)
+	}
+	db := dynamo.NewFromIface(client)
+	// stream reads are free and polled continuously, so they are left out of telemetry
+	streams := dynamodbstreams.New(sess, dbConfig)
+	instrumentRetries(&streams.Handlers)
 	return &DB{
 		dynDB:       db,
-		Environment: environment,
+		streams:     streams,
+		Environment: c.Environment,
+		Region:      aws.StringValue(sess.Config.Region),
 		logger:      logger.NewUILogger(), // start with an empty logger so it can be enables selectively
 	}, nil
 }
+
+// SetLogger for the DB.
+func (db *DB) SetLogger(l *logger.UILogger) {
+	db.logger = l
+}
+
+// Log writes a log message to the UI log.
+func (db *DB) Log(msg string, args ...interface{}) {
+	db.logger.Write("dynamodb", msg, args...)
+}
+
+// Tables returns the sorted names of all tables in the current environment.
+func (db *DB) Tables(ctx context.Context) ([]string, error) {
+	// guregu's ListTables doesn't pass the context on to its requests
+	var names []string
+	err := db.dynDB.Client().ListTablesPagesWithContext(ctx, &ddb.ListTablesInput{}, func(out *ddb.ListTablesOutput, last bool) bool {
+		names = append(names, aws.StringValueSlice(out.TableNames)...)
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	return environmentTables(db.Environment, names), nil
+}
+
+// SetTable sets the table targeted by searches.
+func (db *DB) SetTable(name string) {
+	db.table = name
+}
+
+// Table returns the name of the table targeted by searches.
+func (db *DB) Table() string {
+	if db.table == "" {
+		return db.IntegrationsTable()
+	}
+	return db.table
+}
+
+// tableName returns the full name of a table in the current environment.
+func (db *DB) tableName(name string) string {
+	return db.Environment + "-" + name
+}
+
+// environmentTables filters names down to the tables belonging to environment, sorted.
+func environmentTables(environment string, names []string) []string {
+	var tables []string
+	for _, n := range names {
+		if strings.HasPrefix(n, environment+"-") {
+			tables = append(tables, n)
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
@@ -0,0 +1,151 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDB connects to a stand in for DynamoDB which answers every request with handler.  Options can change the
+// config before connecting.  The stand in is closed when the test ends.
+//
+// Handlers run on the server's goroutines, where require can't stop the test, so they should record what they are
+// sent for the test to check.
+func newTestDB(t *testing.T, handler http.HandlerFunc, options ...func(*Config)) *DB {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c := Config{
+		Credentials: credentials.NewStaticCredentials("local", "local", ""),
+		Region:      "us-east-1",
+		Environment: "dev",
+		Endpoint:    srv.URL,
+	}
+	for _, o := range options {
+		o(&c)
+	}
+	db, err := NewDB(c)
+	require.NoError(t, err)
+	return db
+}
+
+// assumeRoleResponse is how STS answers AssumeRole, with credentials which don't expire during a test.
+const assumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASSUMED</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2100-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/dev/tbdui</Arn>
+      <AssumedRoleId>AROA:tbdui</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+func TestNewDBAssumeRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mfaSerial string
+		token     string
+		tokenErr  error
+		// expectedCode is the token code sent to STS, none when no MFA device is configured
+		expectedCode string
+		expectErr    bool
+	}{
+		{
+			name: "without MFA",
+		},
+		{
+			name:         "with MFA",
+			mfaSerial:    "arn:aws:iam::123456789012:mfa/someone",
+			token:        "123456",
+			expectedCode: "123456",
+		},
+		{
+			name:      "MFA entry cancelled",
+			mfaSerial: "arn:aws:iam::123456789012:mfa/someone",
+			tokenErr:  errors.New("cancelled"),
+			expectErr: true,
+		},
+	}
+
+	accessKey := regexp.MustCompile(`Credential=([^/]+)/`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// a stand in for both STS and DynamoDB, which records the role assumed and the access key DynamoDB
+			// requests are signed with
+			var lock sync.Mutex
+			var assumed []url.Values
+			var signedWith []string
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-Amz-Target") == "" {
+					r.ParseForm()
+					lock.Lock()
+					assumed = append(assumed, r.PostForm)
+					lock.Unlock()
+					w.Header().Set("Content-Type", "text/xml")
+					w.Write([]byte(assumeRoleResponse))
+					return
+				}
+				lock.Lock()
+				if m := accessKey.FindStringSubmatch(r.Header.Get("Authorization")); m != nil {
+					signedWith = append(signedWith, m[1])
+				}
+				lock.Unlock()
+				w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+				w.Write([]byte(`{"TableNames": ["dev-integrations", "staging-integrations"]}`))
+			}
+
+			var asked int
+			db := newTestDB(t, handler, func(c *Config) {
+				c.RoleARN = "arn:aws:iam::123456789012:role/dev"
+				c.MFASerial = tt.mfaSerial
+				c.STSEndpoint = c.Endpoint
+				c.TokenProvider = func() (string, error) {
+					asked++
+					return tt.token, tt.tokenErr
+				}
+			})
+
+			// credentials are only assumed once, so the token is only asked for once
+			for i := 0; i < 2; i++ {
+				tables, err := db.Tables(context.Background())
+				if tt.expectErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, []string{"dev-integrations"}, tables)
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			require.Len(t, assumed, 1)
+			require.Equal(t, "AssumeRole", assumed[0].Get("Action"))
+			require.Equal(t, "arn:aws:iam::123456789012:role/dev", assumed[0].Get("RoleArn"))
+			require.Equal(t, tt.mfaSerial, assumed[0].Get("SerialNumber"))
+			require.Equal(t, tt.expectedCode, assumed[0].Get("TokenCode"))
+			if tt.mfaSerial == "" {
+				require.Equal(t, 0, asked)
+			} else {
+				require.Equal(t, 1, asked)
+			}
+			// DynamoDB is called with the assumed credentials, not the base ones
+			require.Equal(t, []string{"ASSUMED", "ASSUMED"}, signedWith)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Capacity is provisioned throughput, in capacity units per second.
+type Capacity struct {
+	Read  int64
+	Write int64
+}
+
+// TableInfo describes a table: its keys and indexes, how it is billed, roughly how big it is, and its stream and TTL.
+type TableInfo struct {
+	TableSchema
+	// Status is one of CREATING, UPDATING, DELETING or ACTIVE, among others.
+	Status string
+	// Created is zero when the creation time isn't known.
+	Created time.Time
+	// OnDemand is true for tables billed per request, otherwise Capacity is provisioned.
+	OnDemand bool
+	Capacity Capacity
+	// IndexCapacity is the capacity provisioned for each global secondary index, by name.
+	IndexCapacity map[string]Capacity
+	// Items and SizeBytes are approximate.  DynamoDB updates them about every six hours.
+	Items     int64
+	SizeBytes int64
+	// StreamView is what the stream records of each write, e.g. NEW_AND_OLD_IMAGES, when the stream is enabled.
+	StreamEnabled bool
+	StreamView    string
+	// TTL is zero when the TTL configuration couldn't be read.
+	TTL TimeToLive
+}
+
+// Describe reads everything known about a table.  The table is still described when its TTL configuration can't be
+// read, e.g. without permission to describe it, with TTL left zero.
+func (db *DB) Describe(ctx context.Context, table string) (TableInfo, error) {
+	desc, err := db.dynDB.Table(table).Describe().RunWithContext(ctx)
+	if err != nil {
+		return TableInfo{}, fmt.Errorf("failed to describe table %s: %w", table, err)
+	}
+	ttl, err := db.TimeToLive(ctx, table)
+	if err != nil {
+		db.Log("%v", err)
+	}
+
+	info := TableInfo{
+		TableSchema:   schemaOf(desc),
+		Status:        string(desc.Status),
+		Created:       desc.Created,
+		OnDemand:      desc.OnDemand,
+		Capacity:      Capacity{Read: desc.Throughput.Read, Write: desc.Throughput.Write},
+		IndexCapacity: make(map[string]Capacity),
+		Items:         desc.Items,
+		SizeBytes:     desc.Size,
+		StreamEnabled: desc.StreamEnabled,
+		StreamView:    string(desc.StreamView),
+		TTL:           ttl,
+	}
+	for _, i := range desc.GSI {
+		info.IndexCapacity[i.Name] = Capacity{Read: i.Throughput.Read, Write: i.Throughput.Write}
+	}
+	return info, nil
+}
+
+// Describe reads everything known about a table.  Memory tables are billed per request and stream every write, and
+// their size is that of their items as JSON.
+func (db *MemoryDB) Describe(ctx context.Context, table string) (TableInfo, error) {
+	schema, err := db.Schema(ctx, table)
+	if err != nil {
+		return TableInfo{}, err
+	}
+	ttl, err := db.TimeToLive(ctx, table)
+	if err != nil {
+		return TableInfo{}, err
+	}
+
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	t, err := db.table(table)
+	if err != nil {
+		return TableInfo{}, err
+	}
+	var size int64
+	for _, i := range t.items {
+		b, err := json.Marshal(i)
+		if err != nil {
+			return TableInfo{}, err
+		}
+		size += int64(len(b))
+	}
+	return TableInfo{
+		TableSchema:   schema,
+		Status:        ddb.TableStatusActive,
+		OnDemand:      true,
+		Items:         int64(len(t.items)),
+		SizeBytes:     size,
+		StreamEnabled: true,
+		StreamView:    ddb.StreamViewTypeNewAndOldImages,
+		TTL:           ttl,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package dynamodb
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryDBDescribe(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	info, err := db.Describe(context.Background(), "dev-integration-sessions")
+	require.NoError(t, err)
+	require.Equal(t, "session_id", info.HashKey)
+	require.True(t, info.OnDemand)
+	require.Equal(t, int64(3), info.Items)
+	require.NotZero(t, info.SizeBytes)
+	require.True(t, info.TTL.Enabled())
+	require.True(t, info.StreamEnabled)
+
+	_, err = db.Describe(context.Background(), "dev-nope")
+	require.Error(t, err)
+}
+
+func TestDBDescribe(t *testing.T) {
+	t.Parallel()
+
+	// a stand in for DynamoDB with a provisioned table, without TTL, which can deny describing TTL
+	var denyTTL int32
+	db := newTestDB(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch target := r.Header.Get("X-Amz-Target"); target[strings.Index(target, ".")+1:] {
+		case "DescribeTable":
+			w.Write([]byte(`{"Table": {"TableName": "dev-integrations", "TableStatus": "ACTIVE", "CreationDateTime": 1600000000,
+				"KeySchema": [{"AttributeName": "integration_id", "KeyType": "HASH"}],
+				"AttributeDefinitions": [{"AttributeName": "integration_id", "AttributeType": "S"}, {"AttributeName": "company_id", "AttributeType": "S"}],
+				"ProvisionedThroughput": {"ReadCapacityUnits": 5, "WriteCapacityUnits": 2},
+				"GlobalSecondaryIndexes": [{"IndexName": "company_id-index", "IndexArn": "arn:aws:dynamodb:us-east-1:000000000000:table/dev-integrations/index/company_id-index", "IndexStatus": "ACTIVE",
+					"KeySchema": [{"AttributeName": "company_id", "KeyType": "HASH"}],
+					"Projection": {"ProjectionType": "KEYS_ONLY"}, "ProvisionedThroughput": {"ReadCapacityUnits": 3, "WriteCapacityUnits": 1}}],
+				"ItemCount": 42, "TableSizeBytes": 4200}}`))
+		case "DescribeTimeToLive":
+			if atomic.LoadInt32(&denyTTL) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"__type": "com.amazonaws.dynamodb.v20120810#AccessDeniedException", "message": "not authorized to perform dynamodb:DescribeTimeToLive"}`))
+				return
+			}
+			w.Write([]byte(`{"TimeToLiveDescription": {"TimeToLiveStatus": "DISABLED"}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "com.amazonaws.dynamodb.v20120810#ValidationException", "message": "unexpected ` + target + `"}`))
+		}
+	})
+
+	info, err := db.Describe(context.Background(), "dev-integrations")
+	require.NoError(t, err)
+	require.Equal(t, "integration_id", info.HashKey)
+	require.Equal(t, "KEYS_ONLY", info.Indexes[0].Projection)
+	require.False(t, info.OnDemand)
+	require.Equal(t, Capacity{Read: 5, Write: 2}, info.Capacity)
+	require.Equal(t, map[string]Capacity{"company_id-index": {Read: 3, Write: 1}}, info.IndexCapacity)
+	require.Equal(t, int64(42), info.Items)
+	require.Equal(t, int64(4200), info.SizeBytes)
+	require.True(t, info.Created.Equal(time.Unix(1600000000, 0)))
+	require.False(t, info.TTL.Enabled())
+	require.Equal(t, "DISABLED", info.TTL.Status)
+
+	// the rest of the table is still described when its TTL can't be
+	atomic.StoreInt32(&denyTTL, 1)
+	info, err = db.Describe(context.Background(), "dev-integrations")
+	require.NoError(t, err)
+	require.Equal(t, int64(42), info.Items)
+	require.Equal(t, TimeToLive{}, info.TTL)
+}
@@ -0,0 +1,63 @@
+package dynamodb
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Change is the kind of difference found for an attribute.
+type Change string
+
+const (
+	Added   Change = "added"
+	Removed Change = "removed"
+	Changed Change = "changed"
+)
+
+// AttributeDiff is a single attribute which differs between two items.  Nested map attributes are compared
+// attribute by attribute and named by their dotted path.
+type AttributeDiff struct {
+	Name   string
+	Change Change
+	// Old is nil for added attributes and New is nil for removed attributes.
+	Old *ddb.AttributeValue
+	New *ddb.AttributeValue
+}
+
+// DiffItems returns the attributes which differ from a to b, sorted by name.
+func DiffItems(a, b Item) []AttributeDiff {
+	var diffs []AttributeDiff
+	diffAttrs("", a, b, &diffs)
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
+
+func diffAttrs(path string, a, b map[string]*ddb.AttributeValue, diffs *[]AttributeDiff) {
+	for name, before := range a {
+		n := path + name
+		after, ok := b[name]
+		switch {
+		case !ok:
+			*diffs = append(*diffs, AttributeDiff{Name: n, Change: Removed, Old: before})
+		case before.M != nil && after.M != nil:
+			diffAttrs(n+".", before.M, after.M, diffs)
+		case !attrsEqual(before, after):
+			*diffs = append(*diffs, AttributeDiff{Name: n, Change: Changed, Old: before, New: after})
+		}
+	}
+	for name, after := range b {
+		if _, ok := a[name]; !ok {
+			*diffs = append(*diffs, AttributeDiff{Name: path + name, Change: Added, New: after})
+		}
+	}
+}
+
+// attrsEqual returns true when both attributes have the same type and value.
+func attrsEqual(a, b *ddb.AttributeValue) bool {
+	x, errA := json.Marshal(typedAttr(a))
+	y, errB := json.Marshal(typedAttr(b))
+	return errA == nil && errB == nil && bytes.Equal(x, y)
+}
@@ -0,0 +1,64 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffItems(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected map[string]Change
+	}{
+		{
+			name:     "identical",
+			a:        `{"id": "a", "n": 1}`,
+			b:        `{"id": "a", "n": 1}`,
+			expected: map[string]Change{},
+		},
+		{
+			name:     "added removed and changed",
+			a:        `{"id": "a", "n": 1, "old": true}`,
+			b:        `{"id": "a", "n": 2, "new": "x"}`,
+			expected: map[string]Change{"n": Changed, "old": Removed, "new": Added},
+		},
+		{
+			name:     "type change",
+			a:        `{"n": 1}`,
+			b:        `{"n": "1"}`,
+			expected: map[string]Change{"n": Changed},
+		},
+		{
+			name:     "nested maps",
+			a:        `{"config": {"url": "a", "retries": 3}}`,
+			b:        `{"config": {"url": "b", "timeout": 5}}`,
+			expected: map[string]Change{"config.url": Changed, "config.retries": Removed, "config.timeout": Added},
+		},
+		{
+			name:     "lists compare whole",
+			a:        `{"tags": ["a", "b"]}`,
+			b:        `{"tags": ["b", "a"]}`,
+			expected: map[string]Change{"tags": Changed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := DecodeItem([]byte(tt.a))
+			require.NoError(t, err)
+			b, err := DecodeItem([]byte(tt.b))
+			require.NoError(t, err)
+
+			changes := map[string]Change{}
+			for _, d := range DiffItems(a, b) {
+				changes[d.Name] = d.Change
+			}
+			require.Equal(t, tt.expected, changes)
+		})
+	}
+}
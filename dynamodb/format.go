@@ -0,0 +1,272 @@
+package dynamodb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Format is a file format items can be exported to.
+type Format string
+
+// Supported formats.
+const (
+	// JSONLines writes one plain JSON object per line.
+	JSONLines Format = "jsonl"
+	// CSV writes a header of attribute columns followed by one row per item.  Nested maps are flattened into
+	// dotted columns while lists and sets are written as JSON.
+	CSV Format = "csv"
+	// DynamoDBJSON writes one {"Item": {...}} object per line, with DynamoDB type descriptors, as read by
+	// "aws dynamodb import-table".
+	DynamoDBJSON Format = "ddb"
+)
+
+// ParseFormat returns the format with the given name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case JSONLines, CSV, DynamoDBJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q, use one of %s, %s or %s", name, JSONLines, CSV, DynamoDBJSON)
+}
+
+// WriteItems writes items to w in the given format.
+func WriteItems(w io.Writer, format Format, items []Item) error {
+	switch format {
+	case JSONLines:
+		return writeLines(w, items, func(i Item) interface{} { return i })
+	case DynamoDBJSON:
+		return writeLines(w, items, func(i Item) interface{} {
+			return map[string]interface{}{"Item": typedItem(i)}
+		})
+	case CSV:
+		return writeCSV(w, items)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// writeLines writes each item as a line of JSON.
+func writeLines(w io.Writer, items []Item, encode func(Item) interface{}) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	for _, i := range items {
+		if err := enc.Encode(encode(i)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// writeCSV writes items with one column for every flattened attribute in any item.
+func writeCSV(w io.Writer, items []Item) error {
+	rows := make([]map[string]string, 0, len(items))
+	columns := map[string]bool{}
+	for _, i := range items {
+		row := map[string]string{}
+		for name, av := range i {
+			flatten(name, av, row)
+		}
+		for c := range row {
+			columns[c] = true
+		}
+		rows = append(rows, row)
+	}
+	header := make([]string, 0, len(columns))
+	for c := range columns {
+		header = append(header, c)
+	}
+	sort.Strings(header)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, c := range header {
+			record[i] = row[c]
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// flatten writes the value of av into row.  Maps are flattened into one column per attribute, named by path.
+func flatten(path string, av *ddb.AttributeValue, row map[string]string) {
+	switch v := attrToJSON(av).(type) {
+	case map[string]interface{}:
+		for name, inner := range av.M {
+			flatten(path+"."+name, inner, row)
+		}
+	case string:
+		row[path] = v
+	case nil:
+		row[path] = ""
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			row[path] = fmt.Sprint(v)
+			return
+		}
+		row[path] = string(b)
+	}
+}
+
+// typedItem converts an item to JSON with DynamoDB type descriptors.
+func typedItem(i Item) map[string]interface{} {
+	m := make(map[string]interface{}, len(i))
+	for k, av := range i {
+		m[k] = typedAttr(av)
+	}
+	return m
+}
+
+// typedAttr converts a value to JSON with a DynamoDB type descriptor, e.g. {"S": "foo"}.
+func typedAttr(av *ddb.AttributeValue) map[string]interface{} {
+	switch {
+	case av.S != nil:
+		return map[string]interface{}{"S": *av.S}
+	case av.N != nil:
+		return map[string]interface{}{"N": *av.N}
+	case av.BOOL != nil:
+		return map[string]interface{}{"BOOL": *av.BOOL}
+	case av.NULL != nil:
+		return map[string]interface{}{"NULL": true}
+	case av.B != nil:
+		return map[string]interface{}{"B": av.B}
+	case av.M != nil:
+		return map[string]interface{}{"M": typedItem(av.M)}
+	case av.L != nil:
+		l := make([]interface{}, 0, len(av.L))
+		for _, e := range av.L {
+			l = append(l, typedAttr(e))
+		}
+		return map[string]interface{}{"L": l}
+	case av.SS != nil:
+		return map[string]interface{}{"SS": aws.StringValueSlice(av.SS)}
+	case av.NS != nil:
+		return map[string]interface{}{"NS": aws.StringValueSlice(av.NS)}
+	case av.BS != nil:
+		return map[string]interface{}{"BS": av.BS}
+	}
+	return map[string]interface{}{"NULL": true}
+}
+
+// ReadItems reads items from JSON Lines.  Each line is either a plain JSON object or a DynamoDB JSON
+// {"Item": {...}} object, as written by WriteItems.  Blank lines are skipped.
+func ReadItems(r io.Reader) ([]Item, error) {
+	var items []Item
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		item, err := readItem(b)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		items = append(items, item)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// readItem decodes a single line of plain or DynamoDB JSON.
+func readItem(b []byte) (Item, error) {
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(b, &wrapper); err != nil {
+		return nil, err
+	}
+	if raw, ok := wrapper["Item"]; ok && len(wrapper) == 1 {
+		var typed map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &typed); err == nil {
+			if item, err := itemFromTyped(typed); err == nil {
+				return item, nil
+			}
+		}
+	}
+	return DecodeItem(b)
+}
+
+// itemFromTyped converts JSON with DynamoDB type descriptors into an item.
+func itemFromTyped(m map[string]json.RawMessage) (Item, error) {
+	item := make(Item, len(m))
+	for k, raw := range m {
+		av, err := attrFromTyped(raw)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", k, err)
+		}
+		item[k] = av
+	}
+	return item, nil
+}
+
+// attrFromTyped converts a value with a DynamoDB type descriptor, e.g. {"S": "foo"}, into an attribute value.
+func attrFromTyped(raw json.RawMessage) (*ddb.AttributeValue, error) {
+	var typed map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &typed); err != nil || len(typed) != 1 {
+		return nil, fmt.Errorf("expected a single type descriptor, got %s", raw)
+	}
+
+	av := &ddb.AttributeValue{}
+	for typ, v := range typed {
+		var err error
+		switch typ {
+		case "S":
+			err = json.Unmarshal(v, &av.S)
+		case "N":
+			err = json.Unmarshal(v, &av.N)
+		case "BOOL":
+			err = json.Unmarshal(v, &av.BOOL)
+		case "NULL":
+			av.NULL = aws.Bool(true)
+		case "B":
+			err = json.Unmarshal(v, &av.B)
+		case "SS":
+			err = json.Unmarshal(v, &av.SS)
+		case "NS":
+			err = json.Unmarshal(v, &av.NS)
+		case "BS":
+			err = json.Unmarshal(v, &av.BS)
+		case "M":
+			var m map[string]json.RawMessage
+			if err = json.Unmarshal(v, &m); err == nil {
+				av.M, err = itemFromTyped(m)
+			}
+		case "L":
+			var l []json.RawMessage
+			if err = json.Unmarshal(v, &l); err == nil {
+				av.L = make([]*ddb.AttributeValue, 0, len(l))
+				for _, e := range l {
+					var inner *ddb.AttributeValue
+					if inner, err = attrFromTyped(e); err != nil {
+						break
+					}
+					av.L = append(av.L, inner)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unknown type descriptor %q", typ)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %w", typ, err)
+		}
+	}
+	return av, nil
+}
@@ -0,0 +1,78 @@
+package dynamodb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteItems(t *testing.T) {
+	t.Parallel()
+
+	items := []Item{
+		{
+			"id":      {S: aws.String("a")},
+			"version": {N: aws.String("2")},
+			"config": {M: map[string]*ddb.AttributeValue{
+				"channel": {S: aws.String("#ops")},
+				"retries": {N: aws.String("3")},
+			}},
+			"tags": {SS: aws.StringSlice([]string{"x", "y"})},
+		},
+		{
+			"id":      {S: aws.String("b")},
+			"enabled": {BOOL: aws.Bool(true)},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		format   Format
+		expected string
+	}{
+		{
+			name:   "json lines",
+			format: JSONLines,
+			expected: `{"config":{"channel":"#ops","retries":3},"id":"a","tags":["x","y"],"version":2}
+{"enabled":true,"id":"b"}
+`,
+		},
+		{
+			name:   "csv",
+			format: CSV,
+			expected: `config.channel,config.retries,enabled,id,tags,version
+#ops,3,,a,"[""x"",""y""]",2
+,,true,b,,
+`,
+		},
+		{
+			name:   "dynamodb json",
+			format: DynamoDBJSON,
+			expected: `{"Item":{"config":{"M":{"channel":{"S":"#ops"},"retries":{"N":"3"}}},"id":{"S":"a"},"tags":{"SS":["x","y"]},"version":{"N":"2"}}}
+{"Item":{"enabled":{"BOOL":true},"id":{"S":"b"}}}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteItems(&buf, tt.format, items))
+			require.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	f, err := ParseFormat("CSV")
+	require.NoError(t, err)
+	require.Equal(t, CSV, f)
+
+	_, err = ParseFormat("xml")
+	require.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/guregu/dynamo"
+)
+
+// ImportPlan is the result of comparing records to import with the items currently stored.
+type ImportPlan struct {
+	// Inserts are records with no stored item.
+	Inserts []Item
+	// Updates are records which differ from the stored item.
+	Updates []Item
+	// Unchanged are records identical to the stored item.
+	Unchanged []Item
+}
+
+// Changes returns every record which needs to be written.
+func (p ImportPlan) Changes() []Item {
+	return append(append([]Item{}, p.Inserts...), p.Updates...)
+}
+
+// Summary describes the plan in a single line.
+func (p ImportPlan) Summary() string {
+	return fmt.Sprintf("%d inserts, %d updates, %d unchanged", len(p.Inserts), len(p.Updates), len(p.Unchanged))
+}
+
+// PlanImport compares records to import with the current items of a table.  Every record must have the key
+// attributes of the table, and no two records may share a key.
+func PlanImport(key KeySchema, records, current []Item) (ImportPlan, error) {
+	// records are numbered from 1, by key
+	seen := make(map[string]int, len(records))
+	for i, r := range records {
+		if r[key.HashKey] == nil || (key.RangeKey != "" && r[key.RangeKey] == nil) {
+			return ImportPlan{}, fmt.Errorf("record %d is missing key attributes %s", i+1, keyNames(key))
+		}
+		k := key.keyString(r)
+		if j, ok := seen[k]; ok {
+			return ImportPlan{}, fmt.Errorf("records %d and %d have the same key", j, i+1)
+		}
+		seen[k] = i + 1
+	}
+
+	byKey := make(map[string]Item, len(current))
+	for _, c := range current {
+		byKey[key.keyString(c)] = c
+	}
+	var plan ImportPlan
+	for _, r := range records {
+		stored := byKey[key.keyString(r)]
+		switch {
+		case stored == nil:
+			plan.Inserts = append(plan.Inserts, r)
+		case itemsEqual(stored, r):
+			plan.Unchanged = append(plan.Unchanged, r)
+		default:
+			plan.Updates = append(plan.Updates, r)
+		}
+	}
+	return plan, nil
+}
+
+// itemsEqual reports whether two items have the same attributes, of the same types, with the same values.
+func itemsEqual(a, b Item) bool {
+	x, errA := json.Marshal(typedItem(a))
+	y, errB := json.Marshal(typedItem(b))
+	return errA == nil && errB == nil && bytes.Equal(x, y)
+}
+
+// GetItems reads the items with the same keys as keys.  Keys with no stored item are skipped.
+func (db *DB) GetItems(ctx context.Context, schema TableSchema, keys []Item) ([]Item, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	keyed := make([]dynamo.Keyed, 0, len(keys))
+	for _, k := range keys {
+		var rangeValue interface{}
+		if schema.RangeKey != "" {
+			rangeValue = k[schema.RangeKey]
+		}
+		keyed = append(keyed, dynamo.Keys{k[schema.HashKey], rangeValue})
+	}
+
+	batch := db.dynDB.Table(schema.Name).Batch(schema.HashKey)
+	if schema.RangeKey != "" {
+		batch = db.dynDB.Table(schema.Name).Batch(schema.HashKey, schema.RangeKey)
+	}
+	var items []Item
+	err := batch.Get(keyed...).AllWithContext(ctx, &items)
+	if err != nil && err != dynamo.ErrNotFound {
+		return nil, fmt.Errorf("failed to get items from %s: %w", schema.Name, err)
+	}
+	return items, nil
+}
+
+// PutItems writes items to a table, replacing any stored items with the same keys.  Items are written with
+// BatchWriteItem in chunks of 25, retrying unprocessed items with backoff.  The number of items written is
+// returned, even on error.
+func (db *DB) PutItems(ctx context.Context, table string, items []Item) (int, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+	puts := make([]interface{}, 0, len(items))
+	for _, i := range items {
+		puts = append(puts, i)
+	}
+	wrote, err := db.dynDB.Table(table).Batch().Write().Put(puts...).RunWithContext(ctx)
+	if err != nil {
+		return wrote, fmt.Errorf("failed to write items to %s after writing %d: %w", table, wrote, err)
+	}
+	return wrote, nil
+}
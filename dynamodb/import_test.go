@@ -0,0 +1,102 @@
+package dynamodb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadItems(t *testing.T) {
+	t.Parallel()
+
+	typed := []Item{
+		{
+			"id":   {S: aws.String("a")},
+			"tags": {SS: aws.StringSlice([]string{"x"})},
+			"blob": {B: []byte("hello")},
+			"list": {L: []*ddb.AttributeValue{{N: aws.String("1")}, {NULL: aws.Bool(true)}}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		expected  []Item
+		expectErr bool
+	}{
+		{
+			name:  "json lines",
+			input: "{\"id\": \"a\", \"n\": 1}\n\n{\"id\": \"b\", \"ok\": true}\n",
+			expected: []Item{
+				{"id": {S: aws.String("a")}, "n": {N: aws.String("1")}},
+				{"id": {S: aws.String("b")}, "ok": {BOOL: aws.Bool(true)}},
+			},
+		},
+		{
+			name: "dynamodb json",
+			input: func() string {
+				var buf bytes.Buffer
+				require.NoError(t, WriteItems(&buf, DynamoDBJSON, typed))
+				return buf.String()
+			}(),
+			expected: typed,
+		},
+		{
+			name:      "invalid line",
+			input:     "{\"id\": \"a\"}\nnope\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ReadItems(strings.NewReader(tt.input))
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, items)
+		})
+	}
+}
+
+func TestPlanImport(t *testing.T) {
+	t.Parallel()
+
+	key := KeySchema{HashKey: "id", HashKeyType: "S"}
+	item := func(id, name string) Item {
+		return Item{"id": {S: aws.String(id)}, "name": {S: aws.String(name)}}
+	}
+	current := []Item{item("a", "alpha"), item("b", "beta")}
+
+	plan, err := PlanImport(key, []Item{item("a", "alpha"), item("b", "BETA"), item("c", "gamma")}, current)
+	require.NoError(t, err)
+	require.Equal(t, []Item{item("c", "gamma")}, plan.Inserts)
+	require.Equal(t, []Item{item("b", "BETA")}, plan.Updates)
+	require.Equal(t, []Item{item("a", "alpha")}, plan.Unchanged)
+	require.Equal(t, []Item{item("c", "gamma"), item("b", "BETA")}, plan.Changes())
+	require.Equal(t, "1 inserts, 1 updates, 1 unchanged", plan.Summary())
+
+	_, err = PlanImport(key, []Item{{"name": {S: aws.String("no key")}}}, current)
+	require.Error(t, err)
+
+	_, err = PlanImport(key, []Item{item("a", "one"), item("a", "two")}, current)
+	require.Error(t, err)
+
+	// numeric keys are matched by value, however they are written
+	key = KeySchema{HashKey: "id", HashKeyType: "N", RangeKey: "at", RangeKeyType: "N"}
+	numbered := func(id, at string) Item {
+		return Item{"id": {N: aws.String(id)}, "at": {N: aws.String(at)}}
+	}
+	plan, err = PlanImport(key, []Item{numbered("1.0", "2"), numbered("1", "3")}, []Item{numbered("1", "2.00")})
+	require.NoError(t, err)
+	require.Len(t, plan.Inserts, 1)
+	require.Len(t, plan.Updates, 1)
+	_, err = PlanImport(key, []Item{numbered("1", "2"), numbered("10e-1", "20e-1")}, nil)
+	require.EqualError(t, err, "records 1 and 2 have the same key")
+}
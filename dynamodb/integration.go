@@ -0,0 +1,141 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+const (
+	// integrationsTable is the base name of the integrations table.  The full name is prefixed with the environment.
+	integrationsTable = "integrations"
+
+	// IntegrationIDKey is the partition key of the integrations table.
+	IntegrationIDKey = "integration_id"
+	// CompanyIDKey is the attribute holding the ID of the company that owns an integration.
+	CompanyIDKey = "company_id"
+)
+
+// ErrNotFound is returned when a requested item does not exist.
+var ErrNotFound = errors.New("item not found")
+
+// Integration is a single integration record.
+type Integration struct {
+	ID        string    `dynamo:"integration_id,hash" json:"integration_id"`
+	CompanyID string    `dynamo:"company_id" json:"company_id"`
+	Name      string    `dynamo:"name" json:"name"`
+	Type      string    `dynamo:"type" json:"type"`
+	Enabled   bool      `dynamo:"enabled" json:"enabled"`
+	Version   int64     `dynamo:"version" json:"version"`
+	CreatedAt time.Time `dynamo:"created_at" json:"created_at"`
+	UpdatedAt time.Time `dynamo:"updated_at" json:"updated_at"`
+
+	// item is the full item the integration was read from, including attributes without a field above.
+	item Item `dynamo:"-"`
+}
+
+// UnmarshalDynamoItem decodes an integration, keeping the full item it was read from.
+func (i *Integration) UnmarshalDynamoItem(item map[string]*ddb.AttributeValue) error {
+	// plain has no methods, so decoding into it doesn't recurse back here
+	type plain Integration
+	var p plain
+	if err := dynamo.UnmarshalItem(item, &p); err != nil {
+		return err
+	}
+	*i = Integration(p)
+	i.item = item
+	return nil
+}
+
+// Item returns the full item the integration was read from.  Integrations that were not read from a table are
+// encoded from their fields.
+func (i Integration) Item() (Item, error) {
+	if i.item != nil {
+		return i.item.Copy(), nil
+	}
+	return dynamo.MarshalItem(i)
+}
+
+// IntegrationsTable returns the name of the integrations table for the current environment.
+func (db *DB) IntegrationsTable() string {
+	return db.tableName(integrationsTable)
+}
+
+// AllIntegrations returns every integration in the target table.
+func (db *DB) AllIntegrations(ctx context.Context) ([]Integration, error) {
+	var integrations []Integration
+	if err := db.dynDB.Table(db.Table()).Scan().AllWithContext(ctx, &integrations); err != nil {
+		return nil, fmt.Errorf("failed to scan integrations: %w", err)
+	}
+	sortIntegrations(integrations)
+	return integrations, nil
+}
+
+// Integration returns the integration with the given ID.  ErrNotFound is returned if it does not exist.
+func (db *DB) Integration(ctx context.Context, id string) (Integration, error) {
+	var i Integration
+	err := db.dynDB.Table(db.Table()).Get(IntegrationIDKey, id).OneWithContext(ctx, &i)
+	if err == dynamo.ErrNotFound {
+		return Integration{}, fmt.Errorf("integration %q: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return Integration{}, fmt.Errorf("failed to get integration %q: %w", id, err)
+	}
+	return i, nil
+}
+
+// MatchingIntegrationIDs returns the IDs of all integrations beginning with prefix.
+func (db *DB) MatchingIntegrationIDs(ctx context.Context, prefix string) ([]string, error) {
+	integrations, err := db.SearchIntegrations(ctx, prefix, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(integrations))
+	for _, i := range integrations {
+		ids = append(ids, i.ID)
+	}
+	return ids, nil
+}
+
+// CompanyIntegrations returns all integrations owned by a company.
+func (db *DB) CompanyIntegrations(ctx context.Context, companyID string) ([]Integration, error) {
+	return db.SearchIntegrations(ctx, "", companyID, nil)
+}
+
+// SearchIntegrations returns all integrations whose ID begins with idPrefix and which are owned by companyID.
+// Empty arguments are not used to filter.
+func (db *DB) SearchIntegrations(ctx context.Context, idPrefix, companyID string, projection Projection) ([]Integration, error) {
+	scan := db.dynDB.Table(db.Table()).Scan()
+	if idPrefix != "" {
+		scan = scan.Filter("begins_with($, ?)", IntegrationIDKey, idPrefix)
+	}
+	if companyID != "" {
+		scan = scan.Filter("$ = ?", CompanyIDKey, companyID)
+	}
+	if len(projection) > 0 {
+		paths, err := projection.quoted()
+		if err != nil {
+			return nil, err
+		}
+		scan = scan.Project(paths...)
+	}
+
+	var integrations []Integration
+	if err := scan.AllWithContext(ctx, &integrations); err != nil {
+		return nil, fmt.Errorf("failed to search integrations: %w", err)
+	}
+	sortIntegrations(integrations)
+	return integrations, nil
+}
+
+// sortIntegrations orders integrations by ID, since scans return them in no particular order.
+func sortIntegrations(integrations []Integration) {
+	sort.Slice(integrations, func(i, j int) bool {
+		return integrations[i].ID < integrations[j].ID
+	})
+}
@@ -0,0 +1,199 @@
+package dynamodb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Item is a single untyped DynamoDB item.
+type Item map[string]*ddb.AttributeValue
+
+// ItemFromJSON converts plain decoded JSON into an item.  Numbers should be decoded as json.Number to avoid losing
+// precision.
+func ItemFromJSON(m map[string]interface{}) (Item, error) {
+	item := make(Item, len(m))
+	for k, v := range m {
+		av, err := attrFromJSON(v)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", k, err)
+		}
+		item[k] = av
+	}
+	return item, nil
+}
+
+// DecodeItem decodes a plain JSON object into an item.
+func DecodeItem(b []byte) (Item, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return ItemFromJSON(m)
+}
+
+// JSON converts the item to a plain JSON compatible value.
+func (i Item) JSON() map[string]interface{} {
+	m := make(map[string]interface{}, len(i))
+	for k, av := range i {
+		m[k] = attrToJSON(av)
+	}
+	return m
+}
+
+// MarshalJSON encodes the item as plain JSON, dropping DynamoDB type information.
+func (i Item) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.JSON())
+}
+
+// Names returns the sorted attribute names of the item.
+func (i Item) Names() []string {
+	names := make([]string, 0, len(i))
+	for k := range i {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Copy returns a deep copy of the item.
+func (i Item) Copy() Item {
+	if i == nil {
+		return nil
+	}
+	c := make(Item, len(i))
+	for k, av := range i {
+		c[k] = copyAttr(av)
+	}
+	return c
+}
+
+func attrFromJSON(v interface{}) (*ddb.AttributeValue, error) {
+	switch x := v.(type) {
+	case nil:
+		return &ddb.AttributeValue{NULL: aws.Bool(true)}, nil
+	case string:
+		return &ddb.AttributeValue{S: aws.String(x)}, nil
+	case bool:
+		return &ddb.AttributeValue{BOOL: aws.Bool(x)}, nil
+	case json.Number:
+		return &ddb.AttributeValue{N: aws.String(x.String())}, nil
+	case float64:
+		return &ddb.AttributeValue{N: aws.String(strconv.FormatFloat(x, 'f', -1, 64))}, nil
+	case []interface{}:
+		l := make([]*ddb.AttributeValue, 0, len(x))
+		for _, e := range x {
+			av, err := attrFromJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, av)
+		}
+		return &ddb.AttributeValue{L: l}, nil
+	case map[string]interface{}:
+		m, err := ItemFromJSON(x)
+		if err != nil {
+			return nil, err
+		}
+		return &ddb.AttributeValue{M: m}, nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON type %T", v)
+	}
+}
+
+func attrToJSON(av *ddb.AttributeValue) interface{} {
+	switch {
+	case av == nil:
+		return nil
+	case av.S != nil:
+		return *av.S
+	case av.N != nil:
+		return json.Number(*av.N)
+	case av.BOOL != nil:
+		return *av.BOOL
+	case av.NULL != nil:
+		return nil
+	case av.B != nil:
+		return base64.StdEncoding.EncodeToString(av.B)
+	case av.M != nil:
+		return Item(av.M).JSON()
+	case av.L != nil:
+		l := make([]interface{}, 0, len(av.L))
+		for _, e := range av.L {
+			l = append(l, attrToJSON(e))
+		}
+		return l
+	case av.SS != nil:
+		return aws.StringValueSlice(av.SS)
+	case av.NS != nil:
+		l := make([]interface{}, 0, len(av.NS))
+		for _, n := range av.NS {
+			l = append(l, json.Number(*n))
+		}
+		return l
+	case av.BS != nil:
+		l := make([]interface{}, 0, len(av.BS))
+		for _, b := range av.BS {
+			l = append(l, base64.StdEncoding.EncodeToString(b))
+		}
+		return l
+	}
+	return nil
+}
+
+func copyAttr(av *ddb.AttributeValue) *ddb.AttributeValue {
+	if av == nil {
+		return nil
+	}
+	c := *av
+	if av.M != nil {
+		c.M = Item(av.M).Copy()
+	}
+	if av.L != nil {
+		c.L = make([]*ddb.AttributeValue, len(av.L))
+		for i := range av.L {
+			c.L[i] = copyAttr(av.L[i])
+		}
+	}
+	return &c
+}
+
+// MarshalDynamoItem satisfies dynamo.ItemMarshaler so items can be written as is.
+func (i Item) MarshalDynamoItem() (map[string]*ddb.AttributeValue, error) {
+	return i, nil
+}
+
+// UnmarshalDynamoItem satisfies dynamo.ItemUnmarshaler so items can be read as is.
+func (i *Item) UnmarshalDynamoItem(item map[string]*ddb.AttributeValue) error {
+	*i = item
+	return nil
+}
+
+// ApplyJSONEdits decodes an edited plain JSON copy of original.  Plain JSON can't express every DynamoDB type, so
+// attributes that were not changed keep their original type, e.g. a string set is not turned into a list.
+func ApplyJSONEdits(original Item, edited []byte) (Item, error) {
+	item, err := DecodeItem(edited)
+	if err != nil {
+		return nil, fmt.Errorf("edited item is not valid JSON: %w", err)
+	}
+	for k, av := range item {
+		orig, ok := original[k]
+		if !ok {
+			continue
+		}
+		a, errA := json.Marshal(attrToJSON(orig))
+		b, errB := json.Marshal(attrToJSON(av))
+		if errA == nil && errB == nil && bytes.Equal(a, b) {
+			item[k] = orig
+		}
+	}
+	return item, nil
+}
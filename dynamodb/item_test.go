@@ -0,0 +1,70 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyJSONEdits(t *testing.T) {
+	t.Parallel()
+
+	original := Item{
+		"id":    {S: aws.String("a")},
+		"tags":  {SS: aws.StringSlice([]string{"x", "y"})},
+		"count": {N: aws.String("1")},
+	}
+
+	tests := []struct {
+		name      string
+		edited    string
+		expected  Item
+		expectErr bool
+	}{
+		{
+			name:   "unchanged attributes keep their type",
+			edited: `{"id": "a", "tags": ["x", "y"], "count": 2}`,
+			expected: Item{
+				"id":    {S: aws.String("a")},
+				"tags":  {SS: aws.StringSlice([]string{"x", "y"})},
+				"count": {N: aws.String("2")},
+			},
+		},
+		{
+			name:   "changed attributes take the JSON type",
+			edited: `{"id": "a", "tags": ["z"], "count": 1}`,
+			expected: Item{
+				"id":    {S: aws.String("a")},
+				"tags":  {L: []*ddb.AttributeValue{{S: aws.String("z")}}},
+				"count": {N: aws.String("1")},
+			},
+		},
+		{
+			name:   "removed and added attributes",
+			edited: `{"id": "a", "enabled": false}`,
+			expected: Item{
+				"id":      {S: aws.String("a")},
+				"enabled": {BOOL: aws.Bool(false)},
+			},
+		},
+		{
+			name:      "invalid JSON",
+			edited:    `{"id": `,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := ApplyJSONEdits(original, []byte(tt.edited))
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, item)
+		})
+	}
+}
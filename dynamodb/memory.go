@@ -0,0 +1,616 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+	"github.com/swtch1/tbdui/logger"
+)
+
+// MemoryDB is an in-memory stand in for DB.  It is loaded from JSON fixtures and never talks to AWS.
+type MemoryDB struct {
+	Environment string
+	// target is the table targeted by searches.  The environment's integrations table is used when it is empty.
+	target string
+
+	// tables and lock are shared with views of the same DB in other environments.
+	tables map[string]*memoryTable
+	lock   *sync.RWMutex
+
+	logger *logger.UILogger
+}
+
+// memoryTable is a single in-memory table.  Items are kept in insertion order.
+type memoryTable struct {
+	name    string
+	key     KeySchema
+	indexes []IndexSchema
+	items   []Item
+	// ttl is the TTL attribute of the table, if it has one.
+	ttl    string
+	stream memoryStream
+}
+
+// Fixtures describes the contents of a MemoryDB.  Items are written as plain JSON.
+type Fixtures struct {
+	Environment string         `json:"environment"`
+	Tables      []TableFixture `json:"tables"`
+}
+
+// TableFixture describes a single table in a fixture file.
+type TableFixture struct {
+	Name    string `json:"name"`
+	HashKey string `json:"hash_key"`
+	// HashKeyType is the DynamoDB type of the hash key, S by default.
+	HashKeyType string `json:"hash_key_type"`
+	RangeKey    string `json:"range_key"`
+	// RangeKeyType is the DynamoDB type of the range key, S by default.
+	RangeKeyType string         `json:"range_key_type"`
+	Indexes      []IndexFixture `json:"indexes"`
+	// TTLAttribute enables TTL on the table, with expiry times held in the named attribute.
+	TTLAttribute string                   `json:"ttl_attribute"`
+	Items        []map[string]interface{} `json:"items"`
+}
+
+// IndexFixture describes a secondary index of a table in a fixture file.  Indexes are global unless local is set,
+// and project all attributes unless a projection is given.
+type IndexFixture struct {
+	Name         string `json:"name"`
+	Local        bool   `json:"local"`
+	HashKey      string `json:"hash_key"`
+	HashKeyType  string `json:"hash_key_type"`
+	RangeKey     string `json:"range_key"`
+	RangeKeyType string `json:"range_key_type"`
+	// Projection is the projection type of the index: ALL, KEYS_ONLY or INCLUDE.  It is ALL by default.
+	Projection string `json:"projection"`
+	// ProjectedAttributes are the non-key attributes projected into an INCLUDE index.
+	ProjectedAttributes []string `json:"projected_attributes"`
+}
+
+// NewMemoryDB instantiates a new, empty, MemoryDB.
+func NewMemoryDB(environment string) *MemoryDB {
+	return &MemoryDB{
+		Environment: environment,
+		tables:      make(map[string]*memoryTable),
+		lock:        &sync.RWMutex{},
+		logger:      logger.NewUILogger(), // start with an empty logger so it can be enables selectively
+	}
+}
+
+// NewMemoryDBFromFile instantiates a new MemoryDB loaded with the fixtures in the file at path.
+func NewMemoryDBFromFile(path string) (*MemoryDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixtures: %w", err)
+	}
+	defer f.Close()
+
+	db := NewMemoryDB("")
+	if err := db.LoadFixtures(f); err != nil {
+		return nil, fmt.Errorf("failed to load fixtures from %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// InEnvironment returns a view of the same tables from another environment.  Writes through either are seen by both.
+func (db *MemoryDB) InEnvironment(environment string) *MemoryDB {
+	return &MemoryDB{
+		Environment: environment,
+		tables:      db.tables,
+		lock:        db.lock,
+		logger:      db.logger,
+	}
+}
+
+// LoadFixtures reads JSON fixtures from r and adds them to the DB.  The DB environment is set from the fixtures
+// when one is given.
+func (db *MemoryDB) LoadFixtures(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var f Fixtures
+	if err := dec.Decode(&f); err != nil {
+		return err
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	if f.Environment != "" {
+		db.Environment = f.Environment
+	}
+	for _, tf := range f.Tables {
+		if tf.Name == "" || tf.HashKey == "" {
+			return fmt.Errorf("table fixtures require a name and hash_key")
+		}
+		t := &memoryTable{
+			name: tf.Name,
+			ttl:  tf.TTLAttribute,
+			key: KeySchema{
+				HashKey:      tf.HashKey,
+				HashKeyType:  defaultKeyType(tf.HashKeyType),
+				RangeKey:     tf.RangeKey,
+				RangeKeyType: defaultKeyType(tf.RangeKeyType),
+			},
+		}
+		if t.key.RangeKey == "" {
+			t.key.RangeKeyType = ""
+		}
+		for _, f := range tf.Indexes {
+			i := IndexSchema{
+				Name:  f.Name,
+				Local: f.Local,
+				KeySchema: KeySchema{
+					HashKey:      f.HashKey,
+					HashKeyType:  defaultKeyType(f.HashKeyType),
+					RangeKey:     f.RangeKey,
+					RangeKeyType: defaultKeyType(f.RangeKeyType),
+				},
+				Projection:          f.Projection,
+				ProjectedAttributes: f.ProjectedAttributes,
+			}
+			if i.Projection == "" {
+				i.Projection = "ALL"
+			}
+			if i.RangeKey == "" {
+				i.RangeKeyType = ""
+			}
+			t.indexes = append(t.indexes, i)
+		}
+		for _, m := range tf.Items {
+			item, err := ItemFromJSON(m)
+			if err != nil {
+				return fmt.Errorf("table %s: %w", tf.Name, err)
+			}
+			if item[t.key.HashKey] == nil {
+				return fmt.Errorf("table %s: item is missing hash key %s", tf.Name, t.key.HashKey)
+			}
+			if t.key.RangeKey != "" && item[t.key.RangeKey] == nil {
+				return fmt.Errorf("table %s: item is missing range key %s", tf.Name, t.key.RangeKey)
+			}
+			t.items = append(t.items, item)
+		}
+		db.tables[t.name] = t
+	}
+	return nil
+}
+
+// defaultKeyType returns typ, or the string type if it is empty.
+func defaultKeyType(typ string) string {
+	if typ == "" {
+		return string(dynamo.StringType)
+	}
+	return typ
+}
+
+// SetLogger for the DB.
+func (db *MemoryDB) SetLogger(l *logger.UILogger) {
+	db.logger = l
+}
+
+// Log writes a log message to the UI log.
+func (db *MemoryDB) Log(msg string, args ...interface{}) {
+	db.logger.Write("memorydb", msg, args...)
+}
+
+// IntegrationsTable returns the name of the integrations table for the current environment.
+func (db *MemoryDB) IntegrationsTable() string {
+	return db.Environment + "-" + integrationsTable
+}
+
+// Tables returns the sorted names of all tables in the current environment.
+func (db *MemoryDB) Tables(ctx context.Context) ([]string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	names := make([]string, 0, len(db.tables))
+	for n := range db.tables {
+		names = append(names, n)
+	}
+	return environmentTables(db.Environment, names), nil
+}
+
+// SetTable sets the table targeted by searches.
+func (db *MemoryDB) SetTable(name string) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	db.target = name
+}
+
+// Table returns the name of the table targeted by searches.
+func (db *MemoryDB) Table() string {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return db.targetTable()
+}
+
+// targetTable returns the name of the table targeted by searches.  The caller must hold the lock.
+func (db *MemoryDB) targetTable() string {
+	if db.target == "" {
+		return db.IntegrationsTable()
+	}
+	return db.target
+}
+
+// table returns the named table, or an error if it does not exist.  The caller must hold the lock.
+func (db *MemoryDB) table(name string) (*memoryTable, error) {
+	t, ok := db.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("table %s does not exist", name)
+	}
+	return t, nil
+}
+
+// integrations decodes every item in the target table that passes the match function.
+func (db *MemoryDB) integrations(match func(Integration) bool) ([]Integration, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	t, err := db.table(db.targetTable())
+	if err != nil {
+		return nil, err
+	}
+	var integrations []Integration
+	for _, item := range t.items {
+		var i Integration
+		if err := dynamo.UnmarshalItem(item, &i); err != nil {
+			return nil, fmt.Errorf("failed to decode integration: %w", err)
+		}
+		if match(i) {
+			integrations = append(integrations, i)
+		}
+	}
+	sortIntegrations(integrations)
+	return integrations, nil
+}
+
+// AllIntegrations returns every integration in the target table.
+func (db *MemoryDB) AllIntegrations(ctx context.Context) ([]Integration, error) {
+	return db.integrations(func(Integration) bool { return true })
+}
+
+// Integration returns the integration with the given ID.  ErrNotFound is returned if it does not exist.
+func (db *MemoryDB) Integration(ctx context.Context, id string) (Integration, error) {
+	integrations, err := db.integrations(func(i Integration) bool { return i.ID == id })
+	if err != nil {
+		return Integration{}, err
+	}
+	if len(integrations) == 0 {
+		return Integration{}, fmt.Errorf("integration %q: %w", id, ErrNotFound)
+	}
+	return integrations[0], nil
+}
+
+// MatchingIntegrationIDs returns the IDs of all integrations beginning with prefix.
+func (db *MemoryDB) MatchingIntegrationIDs(ctx context.Context, prefix string) ([]string, error) {
+	integrations, err := db.SearchIntegrations(ctx, prefix, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(integrations))
+	for _, i := range integrations {
+		ids = append(ids, i.ID)
+	}
+	return ids, nil
+}
+
+// CompanyIntegrations returns all integrations owned by a company.
+func (db *MemoryDB) CompanyIntegrations(ctx context.Context, companyID string) ([]Integration, error) {
+	return db.SearchIntegrations(ctx, "", companyID, nil)
+}
+
+// SearchIntegrations returns all integrations whose ID begins with idPrefix and which are owned by companyID.
+// Empty arguments are not used to filter.
+func (db *MemoryDB) SearchIntegrations(ctx context.Context, idPrefix, companyID string, projection Projection) ([]Integration, error) {
+	integrations, err := db.integrations(func(i Integration) bool {
+		if !strings.HasPrefix(i.ID, idPrefix) {
+			return false
+		}
+		return companyID == "" || i.CompanyID == companyID
+	})
+	if err != nil {
+		return nil, err
+	}
+	// integrations are matched on their whole items, the way filters are applied before projections, then decoded
+	// again from what was projected so fields left out of the projection are left empty
+	if len(projection) == 0 {
+		return integrations, nil
+	}
+	for i := range integrations {
+		var projected Integration
+		if err := dynamo.UnmarshalItem(projection.Apply(integrations[i].item), &projected); err != nil {
+			return nil, fmt.Errorf("failed to decode integration: %w", err)
+		}
+		integrations[i] = projected
+	}
+	return integrations, nil
+}
+
+// Schema reads the key schema of a table.
+func (db *MemoryDB) Schema(ctx context.Context, table string) (TableSchema, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	t, err := db.table(table)
+	if err != nil {
+		return TableSchema{}, err
+	}
+	return TableSchema{Name: t.name, KeySchema: t.key, Indexes: t.indexes}, nil
+}
+
+// Query runs a key condition query.  Results are ordered by sort key.  Items missing the queried keys are left
+// out, as they would be from a sparse index.
+func (db *MemoryDB) Query(ctx context.Context, q KeyQuery) ([]Item, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	hash, err := keyValue(q.Key.HashKeyType, q.HashValue)
+	if err != nil {
+		return nil, fmt.Errorf("partition key %s: %w", q.Key.HashKey, err)
+	}
+	var values []*ddb.AttributeValue
+	for _, v := range q.RangeValues {
+		av, err := keyValue(q.Key.RangeKeyType, v)
+		if err != nil {
+			return nil, fmt.Errorf("sort key %s: %w", q.Key.RangeKey, err)
+		}
+		values = append(values, av)
+	}
+
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	t, err := db.table(q.Table)
+	if err != nil {
+		return nil, err
+	}
+	var indexed Projection
+	if q.Index != "" {
+		index, ok := (TableSchema{Name: t.name, Indexes: t.indexes}).Index(q.Index)
+		if !ok {
+			return nil, fmt.Errorf("%s has no index %s", t.name, q.Index)
+		}
+		indexed = index.attributes(t.key)
+	}
+
+	var items []Item
+	for _, item := range t.items {
+		if c, ok := compareAttr(item[q.Key.HashKey], hash); !ok || c != 0 {
+			continue
+		}
+		if q.Key.RangeKey != "" && item[q.Key.RangeKey] == nil {
+			continue
+		}
+		if q.RangeOp != "" && !matchRange(item[q.Key.RangeKey], q.RangeOp, values) {
+			continue
+		}
+		items = append(items, q.Projection.Apply(indexed.Apply(item.Copy())))
+	}
+	if q.Key.RangeKey != "" {
+		sort.SliceStable(items, func(i, j int) bool {
+			c, _ := compareAttr(items[i][q.Key.RangeKey], items[j][q.Key.RangeKey])
+			return c < 0
+		})
+	}
+	return items, nil
+}
+
+// attributes returns the attributes an index holds of each item, along with the keys of table, or nil when it holds
+// whole items.
+func (i IndexSchema) attributes(table KeySchema) Projection {
+	if i.Projection == "" || i.Projection == "ALL" {
+		return nil
+	}
+	var p Projection
+	for _, name := range append([]string{table.HashKey, table.RangeKey, i.HashKey, i.RangeKey}, i.ProjectedAttributes...) {
+		if name != "" && !p.includes(name) {
+			p = append(p, name)
+		}
+	}
+	return p
+}
+
+// matchRange reports whether av satisfies a sort key condition.
+func matchRange(av *ddb.AttributeValue, op Operator, values []*ddb.AttributeValue) bool {
+	if av == nil {
+		return false
+	}
+	switch op {
+	case BeginsWith:
+		switch {
+		case av.S != nil && values[0].S != nil:
+			return strings.HasPrefix(*av.S, *values[0].S)
+		case av.B != nil && values[0].B != nil:
+			return bytes.HasPrefix(av.B, values[0].B)
+		}
+		return false
+	case Between:
+		lo, ok := compareAttr(av, values[0])
+		if !ok {
+			return false
+		}
+		hi, ok := compareAttr(av, values[1])
+		return ok && lo >= 0 && hi <= 0
+	}
+
+	c, ok := compareAttr(av, values[0])
+	if !ok {
+		return false
+	}
+	switch op {
+	case Equal:
+		return c == 0
+	case Less:
+		return c < 0
+	case LessOrEqual:
+		return c <= 0
+	case Greater:
+		return c > 0
+	case GreaterOrEqual:
+		return c >= 0
+	}
+	return false
+}
+
+// compareAttr compares two scalar attribute values of the same type the way DynamoDB orders keys.  False is
+// returned if the values cannot be compared.
+func compareAttr(a, b *ddb.AttributeValue) (int, bool) {
+	switch {
+	case a == nil || b == nil:
+		return 0, false
+	case a.S != nil && b.S != nil:
+		return strings.Compare(*a.S, *b.S), true
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B), true
+	case a.N != nil && b.N != nil:
+		x, okA := new(big.Float).SetString(*a.N)
+		y, okB := new(big.Float).SetString(*b.N)
+		if !okA || !okB {
+			return 0, false
+		}
+		return x.Cmp(y), true
+	}
+	return 0, false
+}
+
+// ReplaceItem writes the changes from original to updated, where original must be the item as it was read.
+// ErrConflict is returned if the stored item has changed, following the same rules as DB.ReplaceItem.
+func (db *MemoryDB) ReplaceItem(ctx context.Context, schema TableSchema, original, updated Item) (Item, error) {
+	item, err := prepareReplace(schema, original, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	t, err := db.table(schema.Name)
+	if err != nil {
+		return nil, err
+	}
+	i := t.find(original)
+	if i < 0 || !matchesStored(t.items[i], original) {
+		return nil, fmt.Errorf("failed to write item: %w", ErrConflict)
+	}
+	written := schema.applyChanges(t.items[i], original, item)
+	t.record(t.items[i], written)
+	t.items[i] = written
+	return written.Copy(), nil
+}
+
+// find returns the index of the item with the same key as item, or -1 if there is none.
+func (t *memoryTable) find(item Item) int {
+	for i := range t.items {
+		if t.key.SameKey(t.items[i], item) {
+			return i
+		}
+	}
+	return -1
+}
+
+// DeleteItem deletes the item with the same key as item, returning the item as it was stored.  ErrNotFound is
+// returned if there is no such item.
+func (db *MemoryDB) DeleteItem(ctx context.Context, schema TableSchema, item Item) (Item, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	t, err := db.table(schema.Name)
+	if err != nil {
+		return nil, err
+	}
+	i := t.find(item)
+	if i < 0 {
+		return nil, fmt.Errorf("failed to delete item: %w", ErrNotFound)
+	}
+	old := t.items[i]
+	t.record(old, nil)
+	t.items = append(t.items[:i], t.items[i+1:]...)
+	return old, nil
+}
+
+// CreateItem writes item as long as no item with the same key exists.  ErrExists is returned if one does.
+func (db *MemoryDB) CreateItem(ctx context.Context, schema TableSchema, item Item) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	t, err := db.table(schema.Name)
+	if err != nil {
+		return err
+	}
+	if item[t.key.HashKey] == nil || (t.key.RangeKey != "" && item[t.key.RangeKey] == nil) {
+		return fmt.Errorf("failed to create item: missing key attributes %s", keyNames(t.key))
+	}
+	if t.find(item) >= 0 {
+		return fmt.Errorf("failed to create item: %w", ErrExists)
+	}
+	t.record(nil, item)
+	t.items = append(t.items, item.Copy())
+	return nil
+}
+
+// Scan reads the projected attributes of every item in a table, or whole items when the projection is empty.
+func (db *MemoryDB) Scan(ctx context.Context, table string, projection Projection) ([]Item, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	t, err := db.table(table)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Item, 0, len(t.items))
+	for _, i := range t.items {
+		items = append(items, projection.Apply(i.Copy()))
+	}
+	return items, nil
+}
+
+// Sample reads up to limit items from the start of a table.
+func (db *MemoryDB) Sample(ctx context.Context, table string, limit int) ([]Item, error) {
+	items, err := db.Scan(ctx, table, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// GetItems reads the items with the same keys as keys.  Keys with no stored item are skipped.
+func (db *MemoryDB) GetItems(ctx context.Context, schema TableSchema, keys []Item) ([]Item, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	t, err := db.table(schema.Name)
+	if err != nil {
+		return nil, err
+	}
+	var items []Item
+	for _, k := range keys {
+		if i := t.find(k); i >= 0 {
+			items = append(items, t.items[i].Copy())
+		}
+	}
+	return items, nil
+}
+
+// PutItems writes items to a table, replacing any stored items with the same keys.  The number of items written is
+// returned.
+func (db *MemoryDB) PutItems(ctx context.Context, table string, items []Item) (int, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	t, err := db.table(table)
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range items {
+		if i := t.find(item); i >= 0 {
+			t.record(t.items[i], item)
+			t.items[i] = item.Copy()
+			continue
+		}
+		t.record(nil, item)
+		t.items = append(t.items, item.Copy())
+	}
+	return len(items), nil
+}
@@ -0,0 +1,105 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// memoryPageSize is the number of items returned on each page of a statement run against a MemoryDB.
+const memoryPageSize = 25
+
+var (
+	selectStatement = regexp.MustCompile(`(?is)^select\s+\*\s+from\s+"?([\w.-]+)"?(?:\s+where\s+(.+))?$`)
+	equalCondition  = regexp.MustCompile(`(?s)^"?([\w.-]+)"?\s*=\s*(.+)$`)
+	andSeparator    = regexp.MustCompile(`(?i)\s+and\s+`)
+)
+
+// ExecuteStatement runs a PartiQL statement.  Only statements of the form
+// SELECT * FROM "table" [WHERE attr = value [AND ...]] are supported in memory.
+func (db *MemoryDB) ExecuteStatement(ctx context.Context, statement, nextToken string) (StatementPage, error) {
+	m := selectStatement.FindStringSubmatch(strings.TrimSpace(statement))
+	if m == nil {
+		return StatementPage{}, fmt.Errorf("the in-memory backend only supports SELECT * FROM \"table\" [WHERE attr = value [AND ...]]")
+	}
+	conditions, err := parseEqualConditions(m[2])
+	if err != nil {
+		return StatementPage{}, err
+	}
+	offset := 0
+	if nextToken != "" {
+		if offset, err = strconv.Atoi(nextToken); err != nil {
+			return StatementPage{}, fmt.Errorf("invalid next token %q", nextToken)
+		}
+	}
+
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	t, err := db.table(m[1])
+	if err != nil {
+		return StatementPage{}, err
+	}
+
+	var page StatementPage
+	for i := offset; i < len(t.items); i++ {
+		if !matchAll(t.items[i], conditions) {
+			continue
+		}
+		if len(page.Items) == memoryPageSize {
+			page.NextToken = strconv.Itoa(i)
+			break
+		}
+		page.Items = append(page.Items, t.items[i].Copy())
+	}
+	return page, nil
+}
+
+// parseEqualConditions parses a WHERE clause made of attr = value conditions joined by AND.  Values may be
+// single quoted strings, numbers or booleans.
+func parseEqualConditions(where string) (Item, error) {
+	conditions := Item{}
+	if strings.TrimSpace(where) == "" {
+		return conditions, nil
+	}
+	for _, c := range andSeparator.Split(strings.TrimSpace(where), -1) {
+		m := equalCondition.FindStringSubmatch(strings.TrimSpace(c))
+		if m == nil {
+			return nil, fmt.Errorf("unsupported condition %q, only attr = value is supported in memory", c)
+		}
+		v := strings.TrimSpace(m[2])
+		switch {
+		case len(v) >= 2 && strings.HasPrefix(v, "'") && strings.HasSuffix(v, "'"):
+			conditions[m[1]] = &ddb.AttributeValue{S: aws.String(v[1 : len(v)-1])}
+		case strings.EqualFold(v, "true") || strings.EqualFold(v, "false"):
+			conditions[m[1]] = &ddb.AttributeValue{BOOL: aws.Bool(strings.EqualFold(v, "true"))}
+		default:
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("unsupported value %s, use a 'quoted string', number or boolean", v)
+			}
+			conditions[m[1]] = &ddb.AttributeValue{N: aws.String(v)}
+		}
+	}
+	return conditions, nil
+}
+
+// matchAll reports whether every attribute in conditions is equal in item.
+func matchAll(item, conditions Item) bool {
+	for name, want := range conditions {
+		got := item[name]
+		if want.BOOL != nil {
+			if got == nil || got.BOOL == nil || *got.BOOL != *want.BOOL {
+				return false
+			}
+			continue
+		}
+		if c, ok := compareAttr(got, want); !ok || c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,433 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+const testFixtures = "../test/fixtures/dev.json"
+
+func TestMemoryDBSearchIntegrations(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	require.Equal(t, "dev", db.Environment)
+
+	tests := []struct {
+		name      string
+		idPrefix  string
+		companyID string
+		expected  []string
+	}{
+		{
+			name:     "no filters",
+			expected: []string{"int-salesforce-001", "int-salesforce-002", "int-slack-001", "int-zendesk-001"},
+		},
+		{
+			name:     "id prefix",
+			idPrefix: "int-sales",
+			expected: []string{"int-salesforce-001", "int-salesforce-002"},
+		},
+		{
+			name:      "company",
+			companyID: "acme",
+			expected:  []string{"int-salesforce-001", "int-slack-001"},
+		},
+		{
+			name:      "id prefix and company",
+			idPrefix:  "int-sales",
+			companyID: "acme",
+			expected:  []string{"int-salesforce-001"},
+		},
+		{
+			name:     "no matches",
+			idPrefix: "nope",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			integrations, err := db.SearchIntegrations(context.Background(), tt.idPrefix, tt.companyID, nil)
+			require.NoError(t, err)
+			ids := []string{}
+			for _, i := range integrations {
+				ids = append(ids, i.ID)
+			}
+			require.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestMemoryDBIntegration(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+
+	i, err := db.Integration(context.Background(), "int-slack-001")
+	require.NoError(t, err)
+	require.Equal(t, "acme", i.CompanyID)
+	require.Equal(t, int64(7), i.Version)
+	require.True(t, i.Enabled)
+
+	_, err = db.Integration(context.Background(), "missing")
+	require.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestMemoryDBTables(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+
+	tables, err := db.Tables(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev-company-mappings", "dev-integration-sessions", "dev-integrations", "dev-integrations-archive"}, tables)
+
+	require.Equal(t, "dev-integrations", db.Table())
+	db.SetTable("dev-integrations-archive")
+	ids, err := db.MatchingIntegrationIDs(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"int-hubspot-001"}, ids)
+}
+
+func TestMemoryDBQuery(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	schema, err := db.Schema(context.Background(), "dev-company-mappings")
+	require.NoError(t, err)
+	require.Equal(t, KeySchema{HashKey: "company_id", HashKeyType: "S", RangeKey: "integration_id", RangeKeyType: "S"}, schema.KeySchema)
+
+	tests := []struct {
+		name        string
+		hashValue   string
+		rangeOp     Operator
+		rangeValues []string
+		expected    []string
+	}{
+		{
+			name:      "partition key only",
+			hashValue: "acme",
+			expected:  []string{"int-hubspot-001", "int-salesforce-001", "int-slack-001"},
+		},
+		{
+			name:        "equal",
+			hashValue:   "acme",
+			rangeOp:     Equal,
+			rangeValues: []string{"int-slack-001"},
+			expected:    []string{"int-slack-001"},
+		},
+		{
+			name:        "begins with",
+			hashValue:   "acme",
+			rangeOp:     BeginsWith,
+			rangeValues: []string{"int-s"},
+			expected:    []string{"int-salesforce-001", "int-slack-001"},
+		},
+		{
+			name:        "between",
+			hashValue:   "acme",
+			rangeOp:     Between,
+			rangeValues: []string{"int-h", "int-sb"},
+			expected:    []string{"int-hubspot-001", "int-salesforce-001"},
+		},
+		{
+			name:        "less",
+			hashValue:   "acme",
+			rangeOp:     Less,
+			rangeValues: []string{"int-s"},
+			expected:    []string{"int-hubspot-001"},
+		},
+		{
+			name:        "less or equal",
+			hashValue:   "acme",
+			rangeOp:     LessOrEqual,
+			rangeValues: []string{"int-hubspot-001"},
+			expected:    []string{"int-hubspot-001"},
+		},
+		{
+			name:        "greater",
+			hashValue:   "acme",
+			rangeOp:     Greater,
+			rangeValues: []string{"int-sb"},
+			expected:    []string{"int-slack-001"},
+		},
+		{
+			name:        "greater or equal",
+			hashValue:   "acme",
+			rangeOp:     GreaterOrEqual,
+			rangeValues: []string{"int-slack-001"},
+			expected:    []string{"int-slack-001"},
+		},
+		{
+			name:      "no matching partition",
+			hashValue: "nobody",
+			expected:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := db.Query(context.Background(), KeyQuery{
+				Table:       schema.Name,
+				Key:         schema.KeySchema,
+				HashValue:   tt.hashValue,
+				RangeOp:     tt.rangeOp,
+				RangeValues: tt.rangeValues,
+			})
+			require.NoError(t, err)
+			ids := []string{}
+			for _, i := range items {
+				ids = append(ids, *i["integration_id"].S)
+			}
+			require.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestMemoryDBQueryIndex(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	schema, err := db.Schema(context.Background(), "dev-integrations")
+	require.NoError(t, err)
+	require.Len(t, schema.Indexes, 1)
+	require.Equal(t, "GSI", schema.Indexes[0].Kind())
+
+	key, err := schema.Target("company_id-index")
+	require.NoError(t, err)
+	items, err := db.Query(context.Background(), KeyQuery{
+		Table:       schema.Name,
+		Index:       "company_id-index",
+		Key:         key,
+		HashValue:   "acme",
+		RangeOp:     BeginsWith,
+		RangeValues: []string{"int-sl"},
+	})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "int-slack-001", *items[0]["integration_id"].S)
+
+	_, err = schema.Target("missing-index")
+	require.Error(t, err)
+}
+
+func TestMemoryDBQueryIndexProjection(t *testing.T) {
+	t.Parallel()
+
+	db := NewMemoryDB("dev")
+	require.NoError(t, db.LoadFixtures(strings.NewReader(`{"tables": [{"name": "dev-integrations", "hash_key": "integration_id",
+		"indexes": [{"name": "company_id-index", "hash_key": "company_id", "projection": "KEYS_ONLY"},
+			{"name": "type-index", "hash_key": "type", "projection": "INCLUDE", "projected_attributes": ["name"]}],
+		"items": [{"integration_id": "int-slack-001", "company_id": "acme", "type": "slack", "name": "Slack Alerts", "enabled": true}]}]}`)))
+	schema, err := db.Schema(context.Background(), "dev-integrations")
+	require.NoError(t, err)
+
+	tests := []struct {
+		index    string
+		value    string
+		expected string
+	}{
+		{index: "company_id-index", value: "acme", expected: `{"integration_id": "int-slack-001", "company_id": "acme"}`},
+		{index: "type-index", value: "slack", expected: `{"integration_id": "int-slack-001", "type": "slack", "name": "Slack Alerts"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.index, func(t *testing.T) {
+			key, err := schema.Target(tt.index)
+			require.NoError(t, err)
+			items, err := db.Query(context.Background(), KeyQuery{Table: schema.Name, Index: tt.index, Key: key, HashValue: tt.value})
+			require.NoError(t, err)
+			require.Equal(t, []Item{mustDecodeItem(t, tt.expected)}, items)
+		})
+	}
+}
+
+func TestMemoryDBExecuteStatement(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		statement string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:      "select all",
+			statement: `SELECT * FROM "dev-integrations"`,
+			expected:  []string{"int-salesforce-001", "int-salesforce-002", "int-slack-001", "int-zendesk-001"},
+		},
+		{
+			name:      "select where",
+			statement: `select * from "dev-integrations" where company_id = 'acme' and enabled = true`,
+			expected:  []string{"int-salesforce-001", "int-slack-001"},
+		},
+		{
+			name:      "select where number",
+			statement: `SELECT * FROM "dev-integrations" WHERE "version" = 7`,
+			expected:  []string{"int-slack-001"},
+		},
+		{
+			name:      "unsupported statement",
+			statement: `DELETE FROM "dev-integrations" WHERE integration_id = 'int-slack-001'`,
+			expectErr: true,
+		},
+		{
+			name:      "missing table",
+			statement: `SELECT * FROM "nope"`,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := db.ExecuteStatement(context.Background(), tt.statement, "")
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Empty(t, page.NextToken)
+			ids := []string{}
+			for _, i := range page.Items {
+				ids = append(ids, *i["integration_id"].S)
+			}
+			require.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestMemoryDBReplaceItem(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	schema, err := db.Schema(context.Background(), "dev-integrations")
+	require.NoError(t, err)
+
+	i, err := db.Integration(context.Background(), "int-slack-001")
+	require.NoError(t, err)
+	original, err := i.Item()
+	require.NoError(t, err)
+
+	edited := original.Copy()
+	edited["name"] = &ddb.AttributeValue{S: aws.String("Slack Pages")}
+	written, err := db.ReplaceItem(context.Background(), schema, original, edited)
+	require.NoError(t, err)
+	require.Equal(t, "8", *written[VersionKey].N)
+
+	i, err = db.Integration(context.Background(), "int-slack-001")
+	require.NoError(t, err)
+	require.Equal(t, "Slack Pages", i.Name)
+	require.Equal(t, int64(8), i.Version)
+
+	// writing over the stale original conflicts
+	_, err = db.ReplaceItem(context.Background(), schema, original, edited)
+	require.True(t, errors.Is(err, ErrConflict))
+
+	// keys can't be edited
+	moved := written.Copy()
+	moved[IntegrationIDKey] = &ddb.AttributeValue{S: aws.String("int-slack-002")}
+	_, err = db.ReplaceItem(context.Background(), schema, written, moved)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrConflict))
+}
+
+func TestMemoryDBReplaceItemWithoutVersion(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	schema, err := db.Schema(context.Background(), "dev-company-mappings")
+	require.NoError(t, err)
+
+	items, err := db.Query(context.Background(), KeyQuery{Table: schema.Name, Key: schema.KeySchema, HashValue: "globex"})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	original := items[0]
+
+	// an attribute added since the read is kept, since only changed attributes are written
+	added := original.Copy()
+	added["note"] = &ddb.AttributeValue{S: aws.String("added elsewhere")}
+	_, err = db.ReplaceItem(context.Background(), schema, original, added)
+	require.NoError(t, err)
+
+	edited := original.Copy()
+	edited["role"] = &ddb.AttributeValue{S: aws.String("backup")}
+	written, err := db.ReplaceItem(context.Background(), schema, original, edited)
+	require.NoError(t, err)
+	require.Equal(t, "backup", *written["role"].S)
+	require.Equal(t, "added elsewhere", *written["note"].S)
+
+	// any attribute changing since the read conflicts
+	_, err = db.ReplaceItem(context.Background(), schema, original, edited)
+	require.True(t, errors.Is(err, ErrConflict))
+}
+
+func TestMemoryDBDeleteAndCreateItem(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	schema, err := db.Schema(context.Background(), "dev-company-mappings")
+	require.NoError(t, err)
+
+	key := Item{
+		"company_id":     {S: aws.String("acme")},
+		"integration_id": {S: aws.String("int-slack-001")},
+	}
+	old, err := db.DeleteItem(context.Background(), schema, key)
+	require.NoError(t, err)
+	require.Equal(t, "alerts", *old["role"].S)
+
+	_, err = db.DeleteItem(context.Background(), schema, key)
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	require.NoError(t, db.CreateItem(context.Background(), schema, old))
+	items, err := db.Query(context.Background(), KeyQuery{Table: schema.Name, Key: schema.KeySchema, HashValue: "acme", RangeOp: Equal, RangeValues: []string{"int-slack-001"}})
+	require.NoError(t, err)
+	require.Equal(t, []Item{old}, items)
+
+	err = db.CreateItem(context.Background(), schema, old)
+	require.True(t, errors.Is(err, ErrExists))
+}
+
+func TestMemoryDBInEnvironment(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	staging := db.InEnvironment("staging")
+
+	tables, err := staging.Tables(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"staging-integrations"}, tables)
+
+	i, err := staging.Integration(context.Background(), "int-salesforce-001")
+	require.NoError(t, err)
+	require.False(t, i.Enabled)
+	require.Equal(t, "dev", db.Environment)
+
+	// writes are shared between environments
+	schema, err := staging.Schema(context.Background(), staging.Table())
+	require.NoError(t, err)
+	item, err := DecodeItem([]byte(`{"integration_id": "int-new-001"}`))
+	require.NoError(t, err)
+	require.NoError(t, staging.CreateItem(context.Background(), schema, item))
+	ids, err := db.InEnvironment("staging").MatchingIntegrationIDs(context.Background(), "int-new")
+	require.NoError(t, err)
+	require.Equal(t, []string{"int-new-001"}, ids)
+}
@@ -0,0 +1,40 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// StatementPage is a single page of results from a PartiQL statement.
+type StatementPage struct {
+	Items []Item
+	// NextToken continues the statement on the next page.  It is empty on the last page.
+	NextToken string
+}
+
+// ExecuteStatement runs a PartiQL statement (SELECT, INSERT, UPDATE or DELETE).  Pass the NextToken of a previous
+// page to continue a SELECT.
+func (db *DB) ExecuteStatement(ctx context.Context, statement, nextToken string) (StatementPage, error) {
+	statement = strings.TrimSpace(statement)
+	if statement == "" {
+		return StatementPage{}, fmt.Errorf("a PartiQL statement is required")
+	}
+	in := &ddb.ExecuteStatementInput{Statement: aws.String(statement)}
+	if nextToken != "" {
+		in.NextToken = aws.String(nextToken)
+	}
+
+	out, err := db.dynDB.Client().ExecuteStatementWithContext(ctx, in)
+	if err != nil {
+		return StatementPage{}, fmt.Errorf("failed to execute statement: %w", err)
+	}
+	page := StatementPage{NextToken: aws.StringValue(out.NextToken)}
+	for _, item := range out.Items {
+		page.Items = append(page.Items, item)
+	}
+	return page, nil
+}
@@ -0,0 +1,193 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"sort"
+
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// maxExamples is the number of distinct example values kept for each attribute.
+const maxExamples = 3
+
+// maxExampleLength is the length example values are cut down to.
+const maxExampleLength = 40
+
+// AttributeProfile describes how a single attribute path is used across profiled items.  Nested map attributes are
+// named by their dotted path, and the elements of lists by the path of the list followed by [].
+type AttributeProfile struct {
+	Path string
+	// Count is the number of items holding the attribute.
+	Count int
+	// Types counts the values of the attribute by DynamoDB type, e.g. S or N.  Elements of a list are counted one by
+	// one, so they can add up to more than Count.
+	Types map[string]int
+	// Examples are distinct values of the attribute, in the order they were found.
+	Examples []string
+}
+
+// TypeNames returns the types of the attribute, most common first.
+func (a AttributeProfile) TypeNames() []string {
+	names := make([]string, 0, len(a.Types))
+	for t := range a.Types {
+		names = append(names, t)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if a.Types[names[i]] != a.Types[names[j]] {
+			return a.Types[names[i]] > a.Types[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Mixed returns true when the attribute has been found with more than one type.
+func (a AttributeProfile) Mixed() bool {
+	return len(a.Types) > 1
+}
+
+// ProfileOrder is the column a TableProfile is sorted by.
+type ProfileOrder int
+
+const (
+	ByPath ProfileOrder = iota
+	ByCount
+	ByTypes
+)
+
+func (o ProfileOrder) String() string {
+	switch o {
+	case ByCount:
+		return "count"
+	case ByTypes:
+		return "types"
+	default:
+		return "path"
+	}
+}
+
+// Next returns the order after o, wrapping around to the first.
+func (o ProfileOrder) Next() ProfileOrder {
+	return (o + 1) % (ByTypes + 1)
+}
+
+// TableProfile holds the attributes found in a set of items from a table.
+type TableProfile struct {
+	Table string
+	// Items is the number of items profiled.
+	Items      int
+	Attributes []AttributeProfile
+	Order      ProfileOrder
+}
+
+// ProfileItems infers the attributes of a table from some of its items, sorted by path.
+func ProfileItems(table string, items []Item) TableProfile {
+	paths := make(map[string]*AttributeProfile)
+	for _, i := range items {
+		seen := make(map[string]bool)
+		profileAttrs("", i, paths, seen)
+		for path := range seen {
+			paths[path].Count++
+		}
+	}
+
+	p := TableProfile{Table: table, Items: len(items)}
+	for _, a := range paths {
+		p.Attributes = append(p.Attributes, *a)
+	}
+	p.Sort(ByPath)
+	return p
+}
+
+// Sort the attributes.  Paths sort alphabetically, counts from the most common and types from the most mixed, with
+// ties broken by path.
+func (p *TableProfile) Sort(o ProfileOrder) {
+	p.Order = o
+	attrs := p.Attributes
+	sort.Slice(attrs, func(i, j int) bool {
+		switch {
+		case o == ByCount && attrs[i].Count != attrs[j].Count:
+			return attrs[i].Count > attrs[j].Count
+		case o == ByTypes && len(attrs[i].Types) != len(attrs[j].Types):
+			return len(attrs[i].Types) > len(attrs[j].Types)
+		}
+		return attrs[i].Path < attrs[j].Path
+	})
+}
+
+func profileAttrs(path string, attrs map[string]*ddb.AttributeValue, paths map[string]*AttributeProfile, seen map[string]bool) {
+	for name, av := range attrs {
+		profileAttr(path+name, av, paths, seen)
+	}
+}
+
+func profileAttr(path string, av *ddb.AttributeValue, paths map[string]*AttributeProfile, seen map[string]bool) {
+	a, ok := paths[path]
+	if !ok {
+		a = &AttributeProfile{Path: path, Types: make(map[string]int)}
+		paths[path] = a
+	}
+	seen[path] = true
+	a.Types[attrType(av)]++
+
+	switch {
+	case av.M != nil:
+		profileAttrs(path+".", av.M, paths, seen)
+	case av.L != nil:
+		for _, e := range av.L {
+			profileAttr(path+"[]", e, paths, seen)
+		}
+	default:
+		a.addExample(av)
+	}
+}
+
+// addExample keeps the value of av if it is new and there is room for it.
+func (a *AttributeProfile) addExample(av *ddb.AttributeValue) {
+	if len(a.Examples) >= maxExamples {
+		return
+	}
+	b, err := json.Marshal(attrToJSON(av))
+	if err != nil {
+		return
+	}
+	// cut by characters, not bytes, so multi-byte characters aren't split
+	example := string(b)
+	if r := []rune(example); len(r) > maxExampleLength {
+		example = string(r[:maxExampleLength-3]) + "..."
+	}
+	for _, e := range a.Examples {
+		if e == example {
+			return
+		}
+	}
+	a.Examples = append(a.Examples, example)
+}
+
+// attrType returns the DynamoDB type descriptor of a value, e.g. S or N.
+func attrType(av *ddb.AttributeValue) string {
+	switch {
+	case av.S != nil:
+		return "S"
+	case av.N != nil:
+		return "N"
+	case av.BOOL != nil:
+		return "BOOL"
+	case av.NULL != nil:
+		return "NULL"
+	case av.B != nil:
+		return "B"
+	case av.M != nil:
+		return "M"
+	case av.L != nil:
+		return "L"
+	case av.SS != nil:
+		return "SS"
+	case av.NS != nil:
+		return "NS"
+	case av.BS != nil:
+		return "BS"
+	default:
+		return "?"
+	}
+}
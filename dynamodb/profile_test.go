@@ -0,0 +1,80 @@
+package dynamodb
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileItems(t *testing.T) {
+	t.Parallel()
+
+	var items []Item
+	for _, s := range []string{
+		`{"id": "a", "version": 1, "config": {"url": "x"}, "tags": ["t1", 2]}`,
+		`{"id": "b", "version": "2", "config": {"url": "x", "retries": 3}}`,
+		`{"id": "c", "legacy": true}`,
+	} {
+		i, err := DecodeItem([]byte(s))
+		require.NoError(t, err)
+		items = append(items, i)
+	}
+
+	p := ProfileItems("dev-integrations", items)
+	require.Equal(t, 3, p.Items)
+
+	byPath := map[string]AttributeProfile{}
+	var paths []string
+	for _, a := range p.Attributes {
+		byPath[a.Path] = a
+		paths = append(paths, a.Path)
+	}
+	require.Equal(t, []string{"config", "config.retries", "config.url", "id", "legacy", "tags", "tags[]", "version"}, paths)
+
+	require.Equal(t, 3, byPath["id"].Count)
+	require.Equal(t, []string{`"a"`, `"b"`, `"c"`}, byPath["id"].Examples)
+	require.Equal(t, 2, byPath["config.url"].Count)
+	require.Equal(t, []string{`"x"`}, byPath["config.url"].Examples)
+	require.Equal(t, 1, byPath["tags[]"].Count)
+	require.Equal(t, map[string]int{"S": 1, "N": 1}, byPath["tags[]"].Types)
+	require.True(t, byPath["version"].Mixed())
+	require.False(t, byPath["legacy"].Mixed())
+
+	tests := []struct {
+		order    ProfileOrder
+		expected []string
+	}{
+		{order: ByCount, expected: []string{"id", "config", "config.url", "version"}},
+		{order: ByTypes, expected: []string{"tags[]", "version", "config"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order.String(), func(t *testing.T) {
+			sorted := ProfileItems("dev-integrations", items)
+			sorted.Sort(tt.order)
+			var first []string
+			for _, a := range sorted.Attributes[:len(tt.expected)] {
+				first = append(first, a.Path)
+			}
+			require.Equal(t, tt.expected, first)
+		})
+	}
+}
+
+func TestProfileLongExamples(t *testing.T) {
+	t.Parallel()
+
+	i, err := DecodeItem([]byte(`{"ascii": "` + strings.Repeat("a", 60) + `", "accents": "` + strings.Repeat("é", 60) + `"}`))
+	require.NoError(t, err)
+	byPath := map[string]AttributeProfile{}
+	for _, a := range ProfileItems("dev-integrations", []Item{i}).Attributes {
+		byPath[a.Path] = a
+	}
+
+	require.Equal(t, []string{`"` + strings.Repeat("a", maxExampleLength-4) + "..."}, byPath["ascii"].Examples)
+	// multi-byte characters are kept whole
+	example := byPath["accents"].Examples[0]
+	require.True(t, utf8.ValidString(example))
+	require.Equal(t, `"`+strings.Repeat("é", maxExampleLength-4)+"...", example)
+}
@@ -0,0 +1,255 @@
+package dynamodb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Projection is the attributes to read from each item, as document paths like "name", "config.channel" or
+// "steps[0]".  Reads return whole items when it is empty.
+type Projection []string
+
+// ParseProjection parses a comma separated list of document paths typed by a user.  An empty list projects nothing
+// away.
+func ParseProjection(s string) (Projection, error) {
+	var p Projection
+	for _, path := range strings.Split(s, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if _, err := parsePath(path); err != nil {
+			return nil, err
+		}
+		p = append(p, path)
+	}
+	return p, nil
+}
+
+// String lists the paths of the projection as they would be typed.
+func (p Projection) String() string {
+	return strings.Join(p, ", ")
+}
+
+// WithKeys returns the projection with the attributes of key added, so projected items can still be told apart and
+// written back to.  An empty projection is returned as is, since it already includes everything.
+func (p Projection) WithKeys(key KeySchema) Projection {
+	if len(p) == 0 {
+		return p
+	}
+	projected := append(Projection(nil), p...)
+	for _, k := range []string{key.HashKey, key.RangeKey} {
+		if k != "" && !projected.includes(k) {
+			projected = append(projected, k)
+		}
+	}
+	return projected
+}
+
+// includes returns true when the projection reads the whole of the top level attribute name.
+func (p Projection) includes(name string) bool {
+	for _, path := range p {
+		if path == name {
+			return true
+		}
+	}
+	return false
+}
+
+// pathElement is a step along a document path, either into a map by name or into a list by index.
+type pathElement struct {
+	name  string
+	index int
+}
+
+// parsePath splits a document path into the names and list indexes along it.  Names are never quoted, so names
+// containing dots or brackets can't be projected.
+func parsePath(path string) ([]pathElement, error) {
+	var elements []pathElement
+	for _, part := range strings.Split(path, ".") {
+		name := part
+		var indexes string
+		if i := strings.Index(part, "["); i >= 0 {
+			name, indexes = part[:i], part[i:]
+		}
+		if name == "" || strings.ContainsAny(name, "]'") {
+			return nil, fmt.Errorf("invalid attribute path %q", path)
+		}
+		elements = append(elements, pathElement{name: name})
+
+		for indexes != "" {
+			end := strings.Index(indexes, "]")
+			if indexes[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid attribute path %q", path)
+			}
+			i, err := strconv.Atoi(indexes[1:end])
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("invalid list index in attribute path %q", path)
+			}
+			elements = append(elements, pathElement{index: i})
+			indexes = indexes[end+1:]
+		}
+	}
+	return elements, nil
+}
+
+// quoted returns the paths of the projection with every name in single quotes, which guregu/dynamo substitutes
+// with placeholders so reserved words like "name" can be projected.
+func (p Projection) quoted() ([]string, error) {
+	var paths []string
+	for _, path := range p {
+		elements, err := parsePath(path)
+		if err != nil {
+			return nil, err
+		}
+		var b strings.Builder
+		for i, e := range elements {
+			switch {
+			case e.name == "":
+				fmt.Fprintf(&b, "[%d]", e.index)
+			case i > 0:
+				fmt.Fprintf(&b, ".'%s'", e.name)
+			default:
+				fmt.Fprintf(&b, "'%s'", e.name)
+			}
+		}
+		paths = append(paths, b.String())
+	}
+	return paths, nil
+}
+
+// expression returns the projection as a projection expression and the attribute names it uses, for requests made
+// without guregu/dynamo.
+func (p Projection) expression() (*string, map[string]*string, error) {
+	names := make(map[string]*string)
+	placeholders := make(map[string]string)
+	var paths []string
+	for _, path := range p {
+		elements, err := parsePath(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		var b strings.Builder
+		for i, e := range elements {
+			if e.name == "" {
+				fmt.Fprintf(&b, "[%d]", e.index)
+				continue
+			}
+			placeholder, ok := placeholders[e.name]
+			if !ok {
+				placeholder = fmt.Sprintf("#p%d", len(placeholders))
+				placeholders[e.name] = placeholder
+				names[placeholder] = aws.String(e.name)
+			}
+			if i > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(placeholder)
+		}
+		paths = append(paths, b.String())
+	}
+	return aws.String(strings.Join(paths, ", ")), names, nil
+}
+
+// Apply returns only the projected attributes of item, the way DynamoDB projects them.  Elements picked out of a
+// list keep their order but not their indexes.  Items are returned whole when the projection is empty.
+func (p Projection) Apply(item Item) Item {
+	if len(p) == 0 {
+		return item
+	}
+	tree := &pathTree{}
+	for _, path := range p {
+		elements, err := parsePath(path)
+		if err != nil {
+			continue
+		}
+		tree.add(elements)
+	}
+	projected := tree.apply(&ddb.AttributeValue{M: item})
+	if projected == nil {
+		return Item{}
+	}
+	return projected.M
+}
+
+// pathTree merges the paths of a projection, so paths into the same map or list are projected together.
+type pathTree struct {
+	whole   bool
+	names   map[string]*pathTree
+	indexes map[int]*pathTree
+}
+
+// add the path along elements to the tree.
+func (t *pathTree) add(elements []pathElement) {
+	if len(elements) == 0 {
+		t.whole = true
+		return
+	}
+	e := elements[0]
+	var next *pathTree
+	if e.name != "" {
+		if t.names == nil {
+			t.names = make(map[string]*pathTree)
+		}
+		if next = t.names[e.name]; next == nil {
+			next = &pathTree{}
+			t.names[e.name] = next
+		}
+	} else {
+		if t.indexes == nil {
+			t.indexes = make(map[int]*pathTree)
+		}
+		if next = t.indexes[e.index]; next == nil {
+			next = &pathTree{}
+			t.indexes[e.index] = next
+		}
+	}
+	next.add(elements[1:])
+}
+
+// apply returns a copy of the parts of av along the paths of the tree, or nil when none of them exist.
+func (t *pathTree) apply(av *ddb.AttributeValue) *ddb.AttributeValue {
+	if av == nil {
+		return nil
+	}
+	if t.whole {
+		return copyAttr(av)
+	}
+
+	if av.M != nil && len(t.names) > 0 {
+		m := make(map[string]*ddb.AttributeValue)
+		for name, next := range t.names {
+			if v := next.apply(av.M[name]); v != nil {
+				m[name] = v
+			}
+		}
+		if len(m) > 0 {
+			return &ddb.AttributeValue{M: m}
+		}
+	}
+
+	if av.L != nil && len(t.indexes) > 0 {
+		var indexes []int
+		for i := range t.indexes {
+			if i < len(av.L) {
+				indexes = append(indexes, i)
+			}
+		}
+		sort.Ints(indexes)
+		var l []*ddb.AttributeValue
+		for _, i := range indexes {
+			if v := t.indexes[i].apply(av.L[i]); v != nil {
+				l = append(l, v)
+			}
+		}
+		if len(l) > 0 {
+			return &ddb.AttributeValue{L: l}
+		}
+	}
+	return nil
+}
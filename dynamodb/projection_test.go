@@ -0,0 +1,230 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProjection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		expected  Projection
+		expectErr bool
+	}{
+		{
+			name:  "empty",
+			input: " ",
+		},
+		{
+			name:     "names and paths",
+			input:    "name, config.channel,steps[0].type,",
+			expected: Projection{"name", "config.channel", "steps[0].type"},
+		},
+		{
+			name:     "nested lists",
+			input:    "grid[1][2]",
+			expected: Projection{"grid[1][2]"},
+		},
+		{
+			name:      "empty name",
+			input:     "config..channel",
+			expectErr: true,
+		},
+		{
+			name:      "bad index",
+			input:     "steps[x]",
+			expectErr: true,
+		},
+		{
+			name:      "unclosed index",
+			input:     "steps[0",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseProjection(tt.input)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, p)
+		})
+	}
+}
+
+func TestProjectionWithKeys(t *testing.T) {
+	t.Parallel()
+
+	key := KeySchema{HashKey: "company_id", RangeKey: "integration_id"}
+	require.Empty(t, Projection(nil).WithKeys(key))
+	require.Equal(t, Projection{"name", "company_id", "integration_id"}, Projection{"name"}.WithKeys(key))
+	require.Equal(t, Projection{"company_id", "name", "integration_id"}, Projection{"company_id", "name"}.WithKeys(key))
+}
+
+func TestProjectionApply(t *testing.T) {
+	t.Parallel()
+
+	item := mustDecodeItem(t, `{"id": "a", "name": "Slack", "config": {"channel": "#alerts", "token": "x"},
+		"steps": [{"type": "fetch", "at": 1}, {"type": "send", "at": 2}, {"type": "ack"}]}`)
+
+	tests := []struct {
+		name       string
+		projection Projection
+		expected   string
+	}{
+		{
+			name:     "everything",
+			expected: `{"id": "a", "name": "Slack", "config": {"channel": "#alerts", "token": "x"}, "steps": [{"type": "fetch", "at": 1}, {"type": "send", "at": 2}, {"type": "ack"}]}`,
+		},
+		{
+			name:       "top level attributes",
+			projection: Projection{"id", "name"},
+			expected:   `{"id": "a", "name": "Slack"}`,
+		},
+		{
+			name:       "map attributes",
+			projection: Projection{"config.channel"},
+			expected:   `{"config": {"channel": "#alerts"}}`,
+		},
+		{
+			name:       "list elements keep their order",
+			projection: Projection{"steps[2]", "steps[0].type"},
+			expected:   `{"steps": [{"type": "fetch"}, {"type": "ack"}]}`,
+		},
+		{
+			name:       "missing attributes",
+			projection: Projection{"nope", "config.nope", "steps[5]", "name.nope"},
+			expected:   `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, mustDecodeItem(t, tt.expected), tt.projection.Apply(item.Copy()))
+		})
+	}
+}
+
+func TestMemoryDBProjection(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	ctx := context.Background()
+	projection := Projection{"name", "config.channel"}.WithKeys(KeySchema{HashKey: IntegrationIDKey})
+
+	items, err := db.Query(ctx, KeyQuery{Table: "dev-integrations", Key: KeySchema{HashKey: IntegrationIDKey, HashKeyType: "S"}, HashValue: "int-slack-001", Projection: projection})
+	require.NoError(t, err)
+	require.Equal(t, []Item{mustDecodeItem(t, `{"integration_id": "int-slack-001", "name": "Slack Alerts", "config": {"channel": "#alerts"}}`)}, items)
+
+	items, err = db.ParallelScan(ctx, "dev-integrations", 2, projection)
+	require.NoError(t, err)
+	require.Len(t, items, 4)
+	for _, i := range items {
+		require.Contains(t, i.Names(), "name")
+		require.NotContains(t, i.Names(), "company_id")
+	}
+
+	// searches filter on whole items before projecting them
+	integrations, err := db.SearchIntegrations(ctx, "", "globex", projection)
+	require.NoError(t, err)
+	require.Len(t, integrations, 1)
+	require.Equal(t, "Salesforce Sync", integrations[0].Name)
+	require.Empty(t, integrations[0].CompanyID)
+	item, err := integrations[0].Item()
+	require.NoError(t, err)
+	require.Equal(t, mustDecodeItem(t, `{"integration_id": "int-salesforce-002", "name": "Salesforce Sync"}`), item)
+
+	// the table itself is untouched
+	items, err = db.Scan(ctx, "dev-integrations", nil)
+	require.NoError(t, err)
+	require.Contains(t, items[0].Names(), "company_id")
+
+	// projected results are cached apart from whole ones
+	cache := NewCache(db, time.Minute)
+	items, err = cache.Scan(ctx, "dev-integrations", projection)
+	require.NoError(t, err)
+	require.NotContains(t, items[0].Names(), "company_id")
+	items, err = cache.Scan(ctx, "dev-integrations", nil)
+	require.NoError(t, err)
+	require.Contains(t, items[0].Names(), "company_id")
+}
+
+func TestDBProjection(t *testing.T) {
+	t.Parallel()
+
+	// a stand in for DynamoDB which records the projection of every read, with placeholders swapped for names
+	var lock sync.Mutex
+	projections := make(map[string]string)
+	var decodeErr error
+	placeholder := regexp.MustCompile(`#\w+`)
+	db := newTestDB(t, func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			ProjectionExpression     string
+			ExpressionAttributeNames map[string]string
+		}
+		err := json.NewDecoder(r.Body).Decode(&in)
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		target := r.Header.Get("X-Amz-Target")
+		target = target[strings.Index(target, ".")+1:]
+		lock.Lock()
+		defer lock.Unlock()
+		if err != nil {
+			decodeErr = err
+		}
+		if target == "DescribeTable" {
+			w.Write([]byte(`{"Table": {"TableName": "dev-integrations", "ItemCount": 1, "KeySchema": [{"AttributeName": "integration_id", "KeyType": "HASH"}]}}`))
+			return
+		}
+		projections[target] = placeholder.ReplaceAllStringFunc(in.ProjectionExpression, func(s string) string {
+			return in.ExpressionAttributeNames[s]
+		})
+		w.Write([]byte(`{"Items": [{"integration_id": {"S": "int-slack-001"}, "name": {"S": "Slack Alerts"}}]}`))
+	})
+	recorded := func(target string) string {
+		lock.Lock()
+		defer lock.Unlock()
+		require.NoError(t, decodeErr)
+		projection := projections[target]
+		delete(projections, target)
+		return projection
+	}
+	ctx := context.Background()
+	projection := Projection{"name", "config.channel", "steps[0]"}
+
+	_, err := db.Query(ctx, KeyQuery{Table: "dev-integrations", Key: KeySchema{HashKey: IntegrationIDKey, HashKeyType: "S"}, HashValue: "int-slack-001", Projection: projection})
+	require.NoError(t, err)
+	require.Equal(t, "name, config.channel, steps[0]", recorded("Query"))
+	_, err = db.ParallelScan(ctx, "dev-integrations", 1, projection)
+	require.NoError(t, err)
+	require.Equal(t, "name, config.channel, steps[0]", recorded("Scan"))
+
+	// searches and plain scans project the same way
+	db.SetTable("dev-integrations")
+	integrations, err := db.SearchIntegrations(ctx, "int-", "", projection)
+	require.NoError(t, err)
+	require.Len(t, integrations, 1)
+	require.Equal(t, "name, config.channel, steps[0]", recorded("Scan"))
+	_, err = db.Scan(ctx, "dev-integrations", projection)
+	require.NoError(t, err)
+	require.Equal(t, "name, config.channel, steps[0]", recorded("Scan"))
+
+	_, err = db.Scan(ctx, "dev-integrations", nil)
+	require.NoError(t, err)
+	require.Equal(t, "", recorded("Scan"))
+}
@@ -0,0 +1,182 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/guregu/dynamo"
+)
+
+// Operator compares a sort key against one or more values.
+type Operator string
+
+// Operators supported in sort key conditions.
+const (
+	Equal          Operator = "="
+	BeginsWith     Operator = "begins_with"
+	Between        Operator = "between"
+	Less           Operator = "<"
+	LessOrEqual    Operator = "<="
+	Greater        Operator = ">"
+	GreaterOrEqual Operator = ">="
+)
+
+// dynamoOperators maps operators to their guregu/dynamo equivalents.
+var dynamoOperators = map[Operator]dynamo.Operator{
+	Equal:          dynamo.Equal,
+	BeginsWith:     dynamo.BeginsWith,
+	Between:        dynamo.Between,
+	Less:           dynamo.Less,
+	LessOrEqual:    dynamo.LessOrEqual,
+	Greater:        dynamo.Greater,
+	GreaterOrEqual: dynamo.GreaterOrEqual,
+}
+
+// KeyQuery is a query for all items with a partition key, optionally narrowed by a sort key condition.
+type KeyQuery struct {
+	Table string
+	// Index is the secondary index to query.  The table itself is queried when it is empty.
+	Index string
+	// Key is the key schema of the table or index being queried.
+	Key       KeySchema
+	HashValue string
+	// RangeOp is the sort key condition operator.  No condition is applied when it is empty.
+	RangeOp     Operator
+	RangeValues []string
+	// Projection is the attributes to read from each item.  Whole items are read when it is empty.
+	Projection Projection
+}
+
+// ParseRangeCondition parses a sort key condition typed by a user, e.g. "= foo", "begins_with foo",
+// "between a and b", "< 5", "<= 5", "> 5" or ">= 5".  An empty condition returns an empty operator.
+func ParseRangeCondition(s string) (Operator, []string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil, nil
+	}
+
+	// two character operators are checked first, so "<=" isn't read as "<" followed by "="
+	for _, op := range []Operator{LessOrEqual, GreaterOrEqual, Equal, Less, Greater} {
+		if strings.HasPrefix(s, string(op)) {
+			v := strings.TrimSpace(strings.TrimPrefix(s, string(op)))
+			if v == "" {
+				return "", nil, fmt.Errorf("%s requires a value", op)
+			}
+			return op, []string{v}, nil
+		}
+	}
+
+	fields := strings.Fields(s)
+	switch Operator(strings.ToLower(fields[0])) {
+	case BeginsWith:
+		if len(fields) != 2 {
+			return "", nil, fmt.Errorf("begins_with requires a single value")
+		}
+		return BeginsWith, fields[1:], nil
+	case Between:
+		if len(fields) != 4 || strings.ToLower(fields[2]) != "and" {
+			return "", nil, fmt.Errorf("between requires the form 'between a and b'")
+		}
+		return Between, []string{fields[1], fields[3]}, nil
+	}
+	return "", nil, fmt.Errorf("unknown sort key condition %q, use one of =, begins_with, between, <, <=, >, >=", s)
+}
+
+// target names the table or index being queried.
+func (q KeyQuery) target() string {
+	if q.Index == "" {
+		return q.Table
+	}
+	return q.Table + "/" + q.Index
+}
+
+// validate checks the query can be run against its key schema.
+func (q KeyQuery) validate() error {
+	if q.HashValue == "" {
+		return fmt.Errorf("a partition key value is required")
+	}
+	if q.RangeOp == "" {
+		return nil
+	}
+	if q.Key.RangeKey == "" {
+		return fmt.Errorf("%s has no sort key to apply a condition to", q.target())
+	}
+	if _, ok := dynamoOperators[q.RangeOp]; !ok {
+		return fmt.Errorf("unknown sort key operator %q", q.RangeOp)
+	}
+	want := 1
+	if q.RangeOp == Between {
+		want = 2
+	}
+	if len(q.RangeValues) != want {
+		return fmt.Errorf("%s requires %d value(s)", q.RangeOp, want)
+	}
+	return nil
+}
+
+// Query runs a key condition query.
+func (db *DB) Query(ctx context.Context, q KeyQuery) ([]Item, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	hash, err := keyValue(q.Key.HashKeyType, q.HashValue)
+	if err != nil {
+		return nil, fmt.Errorf("partition key %s: %w", q.Key.HashKey, err)
+	}
+	query := db.dynDB.Table(q.Table).Get(q.Key.HashKey, hash)
+	if q.Index != "" {
+		query = query.Index(q.Index)
+	}
+
+	if q.RangeOp != "" {
+		var values []interface{}
+		for _, v := range q.RangeValues {
+			av, err := keyValue(q.Key.RangeKeyType, v)
+			if err != nil {
+				return nil, fmt.Errorf("sort key %s: %w", q.Key.RangeKey, err)
+			}
+			values = append(values, av)
+		}
+		query = query.Range(q.Key.RangeKey, dynamoOperators[q.RangeOp], values...)
+	}
+	if len(q.Projection) > 0 {
+		paths, err := q.Projection.quoted()
+		if err != nil {
+			return nil, err
+		}
+		query = query.Project(paths...)
+	}
+
+	var items []Item
+	if err := query.AllWithContext(ctx, &items); err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", q.target(), err)
+	}
+	return items, nil
+}
+
+// Scan reads the projected attributes of every item in a table, or whole items when the projection is empty.
+func (db *DB) Scan(ctx context.Context, table string, projection Projection) ([]Item, error) {
+	scan := db.dynDB.Table(table).Scan()
+	if len(projection) > 0 {
+		paths, err := projection.quoted()
+		if err != nil {
+			return nil, err
+		}
+		scan = scan.Project(paths...)
+	}
+	var items []Item
+	if err := scan.AllWithContext(ctx, &items); err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", table, err)
+	}
+	return items, nil
+}
+
+// Sample reads up to limit items from the start of a table.
+func (db *DB) Sample(ctx context.Context, table string, limit int) ([]Item, error) {
+	var items []Item
+	if err := db.dynDB.Table(table).Scan().Limit(int64(limit)).AllWithContext(ctx, &items); err != nil {
+		return nil, fmt.Errorf("failed to sample %s: %w", table, err)
+	}
+	return items, nil
+}
@@ -0,0 +1,105 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRangeCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		condition      string
+		expectedOp     Operator
+		expectedValues []string
+		expectErr      bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:           "equal",
+			condition:      "= foo",
+			expectedOp:     Equal,
+			expectedValues: []string{"foo"},
+		},
+		{
+			name:           "equal without a space",
+			condition:      "=foo",
+			expectedOp:     Equal,
+			expectedValues: []string{"foo"},
+		},
+		{
+			name:           "less",
+			condition:      "< 5",
+			expectedOp:     Less,
+			expectedValues: []string{"5"},
+		},
+		{
+			name:           "less or equal",
+			condition:      "<= 5",
+			expectedOp:     LessOrEqual,
+			expectedValues: []string{"5"},
+		},
+		{
+			name:           "greater",
+			condition:      "> 5",
+			expectedOp:     Greater,
+			expectedValues: []string{"5"},
+		},
+		{
+			name:           "greater or equal",
+			condition:      ">= 5",
+			expectedOp:     GreaterOrEqual,
+			expectedValues: []string{"5"},
+		},
+		{
+			name:           "greater or equal without a space",
+			condition:      ">=5",
+			expectedOp:     GreaterOrEqual,
+			expectedValues: []string{"5"},
+		},
+		{
+			name:           "begins with",
+			condition:      "begins_with 2020-01",
+			expectedOp:     BeginsWith,
+			expectedValues: []string{"2020-01"},
+		},
+		{
+			name:           "between",
+			condition:      "BETWEEN a AND b",
+			expectedOp:     Between,
+			expectedValues: []string{"a", "b"},
+		},
+		{
+			name:      "between missing and",
+			condition: "between a b",
+			expectErr: true,
+		},
+		{
+			name:      "operator without value",
+			condition: "=",
+			expectErr: true,
+		},
+		{
+			name:      "unknown operator",
+			condition: "contains foo",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, values, err := ParseRangeCondition(tt.condition)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedOp, op)
+			require.Equal(t, tt.expectedValues, values)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ScanProgress counts what a scan has read so far.
+type ScanProgress struct {
+	Items int
+	Pages int
+	// Estimate is the approximate number of items in the table, which DynamoDB updates about every six hours.  It is
+	// zero when unknown.
+	Estimate int64
+}
+
+// String describes the progress, with an estimated percentage when the table size is known.
+func (p ScanProgress) String() string {
+	s := fmt.Sprintf("scanned %d items in %d pages", p.Items, p.Pages)
+	if p.Estimate > 0 {
+		percent := int64(p.Items) * 100 / p.Estimate
+		// the estimate is stale, so never claim to be done before the scan is
+		if percent > 99 {
+			percent = 99
+		}
+		s += fmt.Sprintf(", about %d%%", percent)
+	}
+	return s
+}
+
+// scanCounter tracks the progress of a scan shared between workers.
+type scanCounter struct {
+	lock     sync.Mutex
+	progress ScanProgress
+}
+
+// page counts a page of items and reports the progress to the status of ctx.
+func (c *scanCounter) page(ctx context.Context, items int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.progress.Items += items
+	c.progress.Pages++
+	// reported under the lock so the latest status is always the furthest along
+	reportStatus(ctx, "%s", c.progress)
+}
+
+// ParallelScan reads every item in a table, split into segments which are scanned concurrently by one worker each.
+// Progress is reported to the status of ctx after every page.  The first failing segment cancels the others.
+func (db *DB) ParallelScan(ctx context.Context, table string, segments int, projection Projection) ([]Item, error) {
+	if segments < 1 {
+		segments = 1
+	}
+	desc, err := db.dynDB.Table(table).Describe().RunWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe %s: %w", table, err)
+	}
+	var expr *string
+	var names map[string]*string
+	if len(projection) > 0 {
+		if expr, names, err = projection.expression(); err != nil {
+			return nil, err
+		}
+	}
+	counter := &scanCounter{progress: ScanProgress{Estimate: desc.Items}}
+	reportStatus(ctx, "%s", counter.progress)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]Item, segments)
+	errs := make(chan error, segments)
+	var wg sync.WaitGroup
+	for segment := 0; segment < segments; segment++ {
+		wg.Add(1)
+		go func(segment int) {
+			defer wg.Done()
+			in := &ddb.ScanInput{
+				TableName:     aws.String(table),
+				Segment:       aws.Int64(int64(segment)),
+				TotalSegments: aws.Int64(int64(segments)),
+
+				ProjectionExpression:     expr,
+				ExpressionAttributeNames: names,
+			}
+			err := db.dynDB.Client().ScanPagesWithContext(ctx, in, func(out *ddb.ScanOutput, last bool) bool {
+				for _, av := range out.Items {
+					results[segment] = append(results[segment], av)
+				}
+				counter.page(ctx, len(out.Items))
+				return true
+			})
+			if err != nil {
+				errs <- err
+				cancel()
+			}
+		}(segment)
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", table, err)
+	}
+
+	var items []Item
+	for _, r := range results {
+		items = append(items, r...)
+	}
+	db.Log("scanned %d items from %s in %d segments", len(items), table, segments)
+	return items, nil
+}
+
+// ParallelScan reads every item in a table.  Everything is in memory, so it is read at once, as a single page.
+func (db *MemoryDB) ParallelScan(ctx context.Context, table string, segments int, projection Projection) ([]Item, error) {
+	items, err := db.Scan(ctx, table, projection)
+	if err != nil {
+		return nil, err
+	}
+	reportStatus(ctx, "%s", ScanProgress{Items: len(items), Pages: 1, Estimate: int64(len(items))})
+	return items, nil
+}
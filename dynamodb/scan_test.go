@@ -0,0 +1,85 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelScan(t *testing.T) {
+	t.Parallel()
+
+	// a stand in for DynamoDB with one item in each of 4 segments, and a second page for segment 0
+	var requests sync.Mutex
+	var totals []int
+	var decodeErrs []error
+	db := newTestDB(t, func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			Segment           int
+			TotalSegments     int
+			ExclusiveStartKey map[string]interface{}
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			requests.Lock()
+			decodeErrs = append(decodeErrs, err)
+			requests.Unlock()
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.Header.Get("X-Amz-Target") == "DynamoDB_20120810.DescribeTable" {
+			w.Write([]byte(`{"Table": {"TableName": "dev-t", "ItemCount": 10, "KeySchema": [{"AttributeName": "id", "KeyType": "HASH"}]}}`))
+			return
+		}
+		requests.Lock()
+		totals = append(totals, in.TotalSegments)
+		requests.Unlock()
+		if in.Segment == 0 && in.ExclusiveStartKey == nil {
+			fmt.Fprintf(w, `{"Items": [{"id": {"S": "0a"}}], "LastEvaluatedKey": {"id": {"S": "0a"}}}`)
+			return
+		}
+		id := fmt.Sprint(in.Segment)
+		if in.ExclusiveStartKey != nil {
+			id += "b"
+		}
+		fmt.Fprintf(w, `{"Items": [{"id": {"S": %q}}]}`, id)
+	})
+
+	var lock sync.Mutex
+	var statuses []string
+	ctx := WithStatus(context.Background(), func(s string) {
+		lock.Lock()
+		defer lock.Unlock()
+		statuses = append(statuses, s)
+	})
+	items, err := db.ParallelScan(ctx, "dev-t", 4, nil)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, i := range items {
+		ids = append(ids, *i["id"].S)
+	}
+	sort.Strings(ids)
+	require.Equal(t, []string{"0a", "0b", "1", "2", "3"}, ids)
+	require.Equal(t, "scanned 0 items in 0 pages, about 0%", statuses[0])
+	require.Equal(t, "scanned 5 items in 5 pages, about 50%", statuses[len(statuses)-1])
+
+	requests.Lock()
+	defer requests.Unlock()
+	require.Empty(t, decodeErrs)
+	require.Equal(t, []int{4, 4, 4, 4, 4}, totals)
+}
+
+func TestScanProgress(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "scanned 3 items in 1 pages", ScanProgress{Items: 3, Pages: 1}.String())
+	require.Equal(t, "scanned 150 items in 2 pages, about 99%", ScanProgress{Items: 150, Pages: 2, Estimate: 100}.String())
+}
@@ -0,0 +1,179 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// KeySchema describes the primary key of a table.  Key types are DynamoDB scalar types: S, N or B.
+type KeySchema struct {
+	HashKey      string
+	HashKeyType  string
+	RangeKey     string
+	RangeKeyType string
+}
+
+// TableSchema describes the keys of a table and its secondary indexes.
+type TableSchema struct {
+	Name string
+	KeySchema
+	Indexes []IndexSchema
+}
+
+// IndexSchema describes a secondary index.
+type IndexSchema struct {
+	Name string
+	// Local is true for local secondary indexes, otherwise the index is global.
+	Local bool
+	KeySchema
+	// Projection is the projection type of the index: ALL, KEYS_ONLY or INCLUDE.
+	Projection string
+	// ProjectedAttributes are the non-key attributes projected into an INCLUDE index.
+	ProjectedAttributes []string
+}
+
+// Kind returns GSI or LSI depending on the type of index.
+func (i IndexSchema) Kind() string {
+	if i.Local {
+		return "LSI"
+	}
+	return "GSI"
+}
+
+// Index returns the named index.  False is returned if the table has no such index.
+func (s TableSchema) Index(name string) (IndexSchema, bool) {
+	for _, i := range s.Indexes {
+		if i.Name == name {
+			return i, true
+		}
+	}
+	return IndexSchema{}, false
+}
+
+// Target returns the key schema of the named index, or of the table itself when index is empty.
+func (s TableSchema) Target(index string) (KeySchema, error) {
+	if index == "" {
+		return s.KeySchema, nil
+	}
+	i, ok := s.Index(index)
+	if !ok {
+		return KeySchema{}, fmt.Errorf("%s has no index %s", s.Name, index)
+	}
+	return i.KeySchema, nil
+}
+
+// Schema reads the key schema of a table.
+func (db *DB) Schema(ctx context.Context, table string) (TableSchema, error) {
+	desc, err := db.dynDB.Table(table).Describe().RunWithContext(ctx)
+	if err != nil {
+		return TableSchema{}, fmt.Errorf("failed to describe table %s: %w", table, err)
+	}
+	return schemaOf(desc), nil
+}
+
+// schemaOf reads the key schema out of a table description.
+func schemaOf(desc dynamo.Description) TableSchema {
+	schema := TableSchema{
+		Name: desc.Name,
+		KeySchema: KeySchema{
+			HashKey:      desc.HashKey,
+			HashKeyType:  string(desc.HashKeyType),
+			RangeKey:     desc.RangeKey,
+			RangeKeyType: string(desc.RangeKeyType),
+		},
+	}
+	for _, i := range append(desc.GSI, desc.LSI...) {
+		schema.Indexes = append(schema.Indexes, IndexSchema{
+			Name:  i.Name,
+			Local: i.Local,
+			KeySchema: KeySchema{
+				HashKey:      i.HashKey,
+				HashKeyType:  string(i.HashKeyType),
+				RangeKey:     i.RangeKey,
+				RangeKeyType: string(i.RangeKeyType),
+			},
+			Projection:          string(i.ProjectionType),
+			ProjectedAttributes: i.ProjectionAttribs,
+		})
+	}
+	return schema
+}
+
+// keyValue converts user input into an attribute value of the given key type.
+func keyValue(typ, s string) (*ddb.AttributeValue, error) {
+	switch typ {
+	case string(dynamo.NumberType):
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		return &ddb.AttributeValue{N: aws.String(s)}, nil
+	case string(dynamo.BinaryType):
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not base64 encoded binary", s)
+		}
+		return &ddb.AttributeValue{B: b}, nil
+	default:
+		return &ddb.AttributeValue{S: aws.String(s)}, nil
+	}
+}
+
+// KeyOf returns the key attributes of an item.
+func (k KeySchema) KeyOf(item Item) Item {
+	key := Item{k.HashKey: item[k.HashKey]}
+	if k.RangeKey != "" {
+		key[k.RangeKey] = item[k.RangeKey]
+	}
+	return key
+}
+
+// keyString returns the key attributes of item as a string which is the same for items with the same key, so items
+// can be looked up by key.  Numbers are written exactly as fractions, since "1" and "1.0" are the same number to
+// DynamoDB.
+func (k KeySchema) keyString(item Item) string {
+	var b strings.Builder
+	for _, name := range []string{k.HashKey, k.RangeKey} {
+		if name == "" {
+			continue
+		}
+		av := item[name]
+		switch {
+		case av == nil:
+			b.WriteString("-")
+		case av.N != nil:
+			if r, ok := new(big.Rat).SetString(*av.N); ok {
+				fmt.Fprintf(&b, "N%s", r.RatString())
+			} else {
+				fmt.Fprintf(&b, "N%q", *av.N)
+			}
+		case av.B != nil:
+			fmt.Fprintf(&b, "B%q", av.B)
+		default:
+			fmt.Fprintf(&b, "S%q", aws.StringValue(av.S))
+		}
+		b.WriteString(" ")
+	}
+	return b.String()
+}
+
+// SameKey reports whether two items have the same key.  Numbers are compared by value, so "1" and "1.0" are the
+// same key.
+func (k KeySchema) SameKey(a, b Item) bool {
+	for _, name := range []string{k.HashKey, k.RangeKey} {
+		if name == "" {
+			continue
+		}
+		if c, ok := compareAttr(a[name], b[name]); !ok || c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// StatusFunc receives progress of requests made with a context from WithStatus, like throttling retries.  It is
+// called from the goroutine making the request, so it shouldn't block.
+type StatusFunc func(status string)
+
+type statusKey struct{}
+
+// WithStatus returns a context which reports the progress of requests made with it to f.
+func WithStatus(ctx context.Context, f StatusFunc) context.Context {
+	return context.WithValue(ctx, statusKey{}, f)
+}
+
+// reportStatus sends a status to the StatusFunc of ctx, if it has one.
+func reportStatus(ctx context.Context, format string, args ...interface{}) {
+	if f, ok := ctx.Value(statusKey{}).(StatusFunc); ok {
+		f(fmt.Sprintf(format, args...))
+	}
+}
+
+// instrumentRetries adds a handler which reports every retry, before backing off, to the status of the request
+// context.
+func instrumentRetries(h *request.Handlers) {
+	h.Retry.PushBack(func(r *request.Request) {
+		// the SDK only decides to retry after the retry handlers have run, so the decision is made here the same way
+		retryable := r.ShouldRetry(r)
+		if r.Retryable != nil {
+			retryable = aws.BoolValue(r.Retryable)
+		}
+		if !retryable || r.RetryCount >= r.MaxRetries() {
+			return
+		}
+		reason := "failed"
+		if request.IsErrorThrottle(r.Error) {
+			reason = "throttled"
+		}
+		reportStatus(r.Context(), "%s %s, retry %d/%d", r.Operation.Name, reason, r.RetryCount+1, r.MaxRetries())
+	})
+}
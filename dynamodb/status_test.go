@@ -0,0 +1,44 @@
+package dynamodb
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStatus(t *testing.T) {
+	t.Parallel()
+
+	// a stand in for DynamoDB which throttles the first request
+	var lock sync.Mutex
+	var requests int
+	db := newTestDB(t, func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests++
+		first := requests == 1
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if first {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "com.amazonaws.dynamodb.v20120810#ProvisionedThroughputExceededException", "message": "slow down"}`))
+			return
+		}
+		w.Write([]byte(`{"TableNames": ["dev-t", "prod-t"]}`))
+	})
+
+	var statuses []string
+	ctx := WithStatus(context.Background(), func(s string) { statuses = append(statuses, s) })
+	tables, err := db.Tables(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev-t"}, tables)
+	require.Equal(t, []string{"ListTables throttled, retry 1/10"}, statuses)
+
+	// cancelled contexts stop requests
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = db.Tables(ctx)
+	require.Error(t, err)
+}
@@ -0,0 +1,237 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+// ErrNoStream is returned when tailing a table without a stream enabled.
+var ErrNoStream = errors.New("table has no stream enabled")
+
+// streamPollInterval is how often shards are polled for new records.  DynamoDB allows up to five reads per second
+// per shard.
+var streamPollInterval = time.Second
+
+// shardRefreshPolls is how many polls pass between looking for new shards, in case open shards were split.
+const shardRefreshPolls = 30
+
+// EventType is the kind of write a stream record was made for.
+type EventType string
+
+const (
+	Insert EventType = "INSERT"
+	Modify EventType = "MODIFY"
+	Remove EventType = "REMOVE"
+)
+
+// StreamRecord is a single write to a table, read from its stream.
+type StreamRecord struct {
+	Event EventType
+	Table string
+	At    time.Time
+	// Keys are the key attributes of the item written.
+	Keys Item
+	// OldImage is the item before the write and NewImage the item after it.  Either is nil when there is no such
+	// item, or the stream doesn't record it.
+	OldImage Item
+	NewImage Item
+}
+
+// Image returns the item as written, or as it was before it was removed.  Only the keys are returned when the
+// stream records no images.
+func (r StreamRecord) Image() Item {
+	switch {
+	case r.NewImage != nil:
+		return r.NewImage
+	case r.OldImage != nil:
+		return r.OldImage
+	default:
+		return r.Keys
+	}
+}
+
+// Diff returns the attributes changed by the write, from the old image to the new one.
+func (r StreamRecord) Diff() []AttributeDiff {
+	return DiffItems(r.OldImage, r.NewImage)
+}
+
+// TailStream follows the stream of a table from its latest records, passing each new record to handle until ctx is
+// done.  Shards are polled in turn, and new shards are followed from their start as the stream splits.
+func (db *DB) TailStream(ctx context.Context, table string, handle func(StreamRecord)) error {
+	desc, err := db.dynDB.Table(table).Describe().RunWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to describe %s: %w", table, err)
+	}
+	if !desc.StreamEnabled || desc.LatestStreamARN == "" {
+		return fmt.Errorf("failed to tail %s: %w", table, ErrNoStream)
+	}
+
+	// iterators are the next positions to read in each open shard
+	iterators := make(map[string]*string)
+	followed := make(map[string]bool)
+	follow := func(position string) error {
+		shards, err := db.openShards(ctx, desc.LatestStreamARN)
+		if err != nil {
+			return err
+		}
+		for _, id := range shards {
+			if followed[id] {
+				continue
+			}
+			out, err := db.streams.GetShardIteratorWithContext(ctx, &dynamodbstreams.GetShardIteratorInput{
+				StreamArn:         aws.String(desc.LatestStreamARN),
+				ShardId:           aws.String(id),
+				ShardIteratorType: aws.String(position),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to read shard %s of %s: %w", id, table, err)
+			}
+			followed[id] = true
+			iterators[id] = out.ShardIterator
+		}
+		return nil
+	}
+	if err := follow(dynamodbstreams.ShardIteratorTypeLatest); err != nil {
+		return err
+	}
+
+	for poll := 1; ; poll++ {
+		closed := false
+		for id, it := range iterators {
+			out, err := db.streams.GetRecordsWithContext(ctx, &dynamodbstreams.GetRecordsInput{ShardIterator: it})
+			if err != nil {
+				return fmt.Errorf("failed to read stream of %s: %w", table, err)
+			}
+			for _, r := range out.Records {
+				handle(streamRecord(table, r))
+			}
+			if out.NextShardIterator == nil {
+				delete(iterators, id)
+				closed = true
+				continue
+			}
+			iterators[id] = out.NextShardIterator
+		}
+
+		if closed || poll%shardRefreshPolls == 0 {
+			if err := follow(dynamodbstreams.ShardIteratorTypeTrimHorizon); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(streamPollInterval):
+		}
+	}
+}
+
+// openShards returns the IDs of the shards of a stream which are still being written to.
+func (db *DB) openShards(ctx context.Context, arn string) ([]string, error) {
+	var ids []string
+	in := &dynamodbstreams.DescribeStreamInput{StreamArn: aws.String(arn)}
+	for {
+		out, err := db.streams.DescribeStreamWithContext(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe stream %s: %w", arn, err)
+		}
+		for _, s := range out.StreamDescription.Shards {
+			if s.SequenceNumberRange == nil || s.SequenceNumberRange.EndingSequenceNumber == nil {
+				ids = append(ids, aws.StringValue(s.ShardId))
+			}
+		}
+		if out.StreamDescription.LastEvaluatedShardId == nil {
+			return ids, nil
+		}
+		in.ExclusiveStartShardId = out.StreamDescription.LastEvaluatedShardId
+	}
+}
+
+// streamRecord converts a record read from the stream of table.
+func streamRecord(table string, r *dynamodbstreams.Record) StreamRecord {
+	record := StreamRecord{Event: EventType(aws.StringValue(r.EventName)), Table: table}
+	if r.Dynamodb != nil {
+		record.At = aws.TimeValue(r.Dynamodb.ApproximateCreationDateTime)
+		record.Keys = r.Dynamodb.Keys
+		record.OldImage = r.Dynamodb.OldImage
+		record.NewImage = r.Dynamodb.NewImage
+	}
+	return record
+}
+
+// maxMemoryStreamRecords is how many records each in-memory stream keeps.
+const maxMemoryStreamRecords = 1000
+
+// memoryStreamPollInterval is how often in-memory streams are polled for new records.
+var memoryStreamPollInterval = 100 * time.Millisecond
+
+// memoryStream holds the latest writes to an in-memory table.
+type memoryStream struct {
+	records []StreamRecord
+	// next is the sequence number of the next record written.  Records are numbered from zero.
+	next int
+}
+
+// record a write to t.  Either image is nil when there was no item before or after the write.
+func (t *memoryTable) record(oldImage, newImage Item) {
+	r := StreamRecord{Table: t.name, At: time.Now(), OldImage: oldImage.Copy(), NewImage: newImage.Copy()}
+	switch {
+	case oldImage == nil:
+		r.Event = Insert
+		r.Keys = t.key.KeyOf(newImage)
+	case newImage == nil:
+		r.Event = Remove
+		r.Keys = t.key.KeyOf(oldImage)
+	default:
+		r.Event = Modify
+		r.Keys = t.key.KeyOf(newImage)
+	}
+	t.stream.records = append(t.stream.records, r)
+	if len(t.stream.records) > maxMemoryStreamRecords {
+		t.stream.records = t.stream.records[len(t.stream.records)-maxMemoryStreamRecords:]
+	}
+	t.stream.next++
+}
+
+// since returns the records from sequence number seq onwards, along with the sequence number to read from next.
+func (s *memoryStream) since(seq int) ([]StreamRecord, int) {
+	first := s.next - len(s.records)
+	if seq < first {
+		seq = first
+	}
+	return append([]StreamRecord(nil), s.records[seq-first:]...), s.next
+}
+
+// TailStream follows every write to a table made after it is called, passing each to handle until ctx is done.
+func (db *MemoryDB) TailStream(ctx context.Context, table string, handle func(StreamRecord)) error {
+	db.lock.RLock()
+	t, err := db.table(table)
+	if err != nil {
+		db.lock.RUnlock()
+		return err
+	}
+	seq := t.stream.next
+	db.lock.RUnlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(memoryStreamPollInterval):
+		}
+
+		var records []StreamRecord
+		db.lock.RLock()
+		records, seq = t.stream.since(seq)
+		db.lock.RUnlock()
+		for _, r := range records {
+			handle(r)
+		}
+	}
+}
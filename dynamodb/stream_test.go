@@ -0,0 +1,105 @@
+package dynamodb
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryDBTailStream(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	schema, err := db.Schema(ctx, "dev-integrations")
+	require.NoError(t, err)
+	key := mustDecodeItem(t, `{"integration_id": "int-salesforce-001"}`)
+	items, err := db.GetItems(ctx, schema, []Item{key})
+	require.NoError(t, err)
+
+	// writes made before the tail starts are not followed
+	require.NoError(t, db.CreateItem(ctx, schema, mustDecodeItem(t, `{"integration_id": "int-before"}`)))
+
+	records := make(chan StreamRecord, 10)
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() {
+		done <- db.TailStream(ctx, "dev-integrations", func(r StreamRecord) { records <- r })
+	}()
+	time.Sleep(2 * memoryStreamPollInterval)
+
+	updated := items[0].Copy()
+	updated["enabled"] = mustDecodeItem(t, `{"enabled": false}`)["enabled"]
+	_, err = db.ReplaceItem(ctx, schema, items[0], updated)
+	require.NoError(t, err)
+	_, err = db.DeleteItem(ctx, schema, mustDecodeItem(t, `{"integration_id": "int-before"}`))
+	require.NoError(t, err)
+
+	modified := <-records
+	require.Equal(t, Modify, modified.Event)
+	require.Equal(t, key, modified.Keys)
+	changed := map[string]Change{}
+	for _, d := range modified.Diff() {
+		changed[d.Name] = d.Change
+	}
+	require.Equal(t, map[string]Change{"enabled": Changed, VersionKey: Changed}, changed)
+
+	removed := <-records
+	require.Equal(t, Remove, removed.Event)
+	require.Nil(t, removed.NewImage)
+	require.Equal(t, "int-before", *removed.Image()[IntegrationIDKey].S)
+
+	cancel()
+	require.Equal(t, context.Canceled, <-done)
+}
+
+func TestDBTailStream(t *testing.T) {
+	t.Parallel()
+
+	// a stand in for DynamoDB and its streams, with a single shard holding a single record
+	var reads int
+	db := newTestDB(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		target := r.Header.Get("X-Amz-Target")
+		switch target[strings.Index(target, ".")+1:] {
+		case "DescribeTable":
+			w.Write([]byte(`{"Table": {"TableName": "dev-integrations", "StreamSpecification": {"StreamEnabled": true, "StreamViewType": "NEW_AND_OLD_IMAGES"},
+				"LatestStreamArn": "arn:aws:dynamodb:us-east-1:000000000000:table/dev-integrations/stream/2021-01-01T00:00:00.000"}}`))
+		case "DescribeStream":
+			w.Write([]byte(`{"StreamDescription": {"Shards": [{"ShardId": "shardId-00000000000000000000-00000000", "SequenceNumberRange": {"StartingSequenceNumber": "100000000000000000001"}}]}}`))
+		case "GetShardIterator":
+			w.Write([]byte(`{"ShardIterator": "it-1"}`))
+		case "GetRecords":
+			reads++
+			if reads > 1 {
+				w.Write([]byte(`{"Records": [], "NextShardIterator": "it-2"}`))
+				return
+			}
+			w.Write([]byte(`{"Records": [{"eventName": "INSERT", "dynamodb": {"Keys": {"integration_id": {"S": "int-new"}},
+				"NewImage": {"integration_id": {"S": "int-new"}, "company_id": {"S": "acme"}}}}], "NextShardIterator": "it-2"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "com.amazonaws.dynamodb.v20120810#ValidationException", "message": "unexpected ` + target + `"}`))
+		}
+	})
+
+	records := make(chan StreamRecord, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- db.TailStream(ctx, "dev-integrations", func(r StreamRecord) { records <- r })
+	}()
+
+	r := <-records
+	cancel()
+	require.Equal(t, context.Canceled, <-done)
+	require.Equal(t, Insert, r.Event)
+	require.Equal(t, "dev-integrations", r.Table)
+	require.Equal(t, "acme", *r.Image()[CompanyIDKey].S)
+	require.Len(t, r.Diff(), 2)
+}
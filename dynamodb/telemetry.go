@@ -0,0 +1,151 @@
+package dynamodb
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// maxLatencies is the number of recent request latencies kept by Telemetry.
+const maxLatencies = 60
+
+// Request is the cost of a single request to DynamoDB.
+type Request struct {
+	Operation string
+	RCU       float64
+	WCU       float64
+	Latency   time.Duration
+	Failed    bool
+}
+
+// Telemetry records the cost of requests made to DynamoDB.  It is safe for concurrent use.
+type Telemetry struct {
+	lock      sync.Mutex
+	last      Request
+	requests  int
+	rcu       float64
+	wcu       float64
+	latencies []time.Duration
+}
+
+// TelemetrySnapshot is the state of Telemetry at a point in time.
+type TelemetrySnapshot struct {
+	Last     Request
+	Requests int
+	// TotalRCU and TotalWCU are the capacity units consumed over the whole session.
+	TotalRCU float64
+	TotalWCU float64
+	// Latencies are the most recent request latencies, oldest first.
+	Latencies []time.Duration
+}
+
+// NewTelemetry instantiates an empty Telemetry.
+func NewTelemetry() *Telemetry {
+	return &Telemetry{}
+}
+
+// Record a completed request.
+func (t *Telemetry) Record(r Request) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.last = r
+	t.requests++
+	t.rcu += r.RCU
+	t.wcu += r.WCU
+	t.latencies = append(t.latencies, r.Latency)
+	if len(t.latencies) > maxLatencies {
+		t.latencies = t.latencies[len(t.latencies)-maxLatencies:]
+	}
+}
+
+// Snapshot returns the current state of the telemetry.
+func (t *Telemetry) Snapshot() TelemetrySnapshot {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return TelemetrySnapshot{
+		Last:      t.last,
+		Requests:  t.requests,
+		TotalRCU:  t.rcu,
+		TotalWCU:  t.wcu,
+		Latencies: append([]time.Duration(nil), t.latencies...),
+	}
+}
+
+// instrument adds handlers which ask DynamoDB for the consumed capacity of every request, and record it along with
+// the request latency.  Inputs without a ReturnConsumedCapacity field, like ExecuteStatement, only record latency.
+func (t *Telemetry) instrument(h *request.Handlers) {
+	h.Build.PushFront(func(r *request.Request) {
+		f := field(r.Params, "ReturnConsumedCapacity")
+		if f.IsValid() && f.IsNil() && f.CanSet() {
+			f.Set(reflect.ValueOf(aws.String(ddb.ReturnConsumedCapacityTotal)))
+		}
+	})
+	h.Complete.PushBack(func(r *request.Request) {
+		req := Request{
+			Operation: r.Operation.Name,
+			Latency:   time.Since(r.AttemptTime),
+			Failed:    r.Error != nil,
+		}
+		for _, cc := range consumedCapacity(r.Data) {
+			rcu, wcu := aws.Float64Value(cc.ReadCapacityUnits), aws.Float64Value(cc.WriteCapacityUnits)
+			if rcu == 0 && wcu == 0 {
+				// only the total is given, so charge it to the kind of operation
+				if isRead(r) {
+					rcu = aws.Float64Value(cc.CapacityUnits)
+				} else {
+					wcu = aws.Float64Value(cc.CapacityUnits)
+				}
+			}
+			req.RCU += rcu
+			req.WCU += wcu
+		}
+		t.Record(req)
+	})
+}
+
+// consumedCapacity returns the consumed capacity reported in a request output, which is a single value for most
+// operations and one value per table for batches and transactions.
+func consumedCapacity(output interface{}) []*ddb.ConsumedCapacity {
+	f := field(output, "ConsumedCapacity")
+	if !f.IsValid() {
+		return nil
+	}
+	switch cc := f.Interface().(type) {
+	case *ddb.ConsumedCapacity:
+		if cc == nil {
+			return nil
+		}
+		return []*ddb.ConsumedCapacity{cc}
+	case []*ddb.ConsumedCapacity:
+		return cc
+	default:
+		return nil
+	}
+}
+
+// field returns the named field of a pointer to a struct, or the zero Value if there is no such field.
+func field(v interface{}, name string) reflect.Value {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return rv.Elem().FieldByName(name)
+}
+
+// isRead returns true for requests which consume read capacity.
+func isRead(r *request.Request) bool {
+	switch r.Operation.Name {
+	case "GetItem", "BatchGetItem", "Query", "Scan", "TransactGetItems":
+		return true
+	case "ExecuteStatement":
+		if in, ok := r.Params.(*ddb.ExecuteStatementInput); ok {
+			return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(aws.StringValue(in.Statement))), "SELECT")
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelemetry(t *testing.T) {
+	t.Parallel()
+
+	// a stand in for DynamoDB which reports the capacity of every scan page, and fails on anything else
+	var lock sync.Mutex
+	var asked []string
+	var decodeErrs []error
+	telemetry := NewTelemetry()
+	db := newTestDB(t, func(w http.ResponseWriter, r *http.Request) {
+		var in map[string]interface{}
+		err := json.NewDecoder(r.Body).Decode(&in)
+		rcc, _ := in["ReturnConsumedCapacity"].(string)
+		lock.Lock()
+		asked = append(asked, rcc)
+		if err != nil {
+			decodeErrs = append(decodeErrs, err)
+		}
+		lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if r.Header.Get("X-Amz-Target") != "DynamoDB_20120810.Scan" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "com.amazonaws.dynamodb.v20120810#ValidationException", "message": "nope"}`))
+			return
+		}
+		w.Write([]byte(`{"Items": [{"id": {"S": "a"}}], "Count": 1, "ConsumedCapacity": {"TableName": "dev-t", "CapacityUnits": 2.5}}`))
+	}, func(c *Config) {
+		c.Telemetry = telemetry
+	})
+
+	items, err := db.Scan(context.Background(), "dev-t", nil)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+
+	_, err = db.Schema(context.Background(), "dev-t")
+	require.Error(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Empty(t, decodeErrs)
+	require.Equal(t, []string{"TOTAL", ""}, asked)
+
+	s := telemetry.Snapshot()
+	require.Equal(t, 2, s.Requests)
+	require.Equal(t, 2.5, s.TotalRCU)
+	require.Equal(t, 0.0, s.TotalWCU)
+	require.Equal(t, "DescribeTable", s.Last.Operation)
+	require.True(t, s.Last.Failed)
+	require.Len(t, s.Latencies, 2)
+}
@@ -0,0 +1,240 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// MaxTransactionChanges is the most changes DynamoDB accepts in a single transaction.
+const MaxTransactionChanges = 100
+
+// Action is the kind of write a staged change makes.
+type Action string
+
+const (
+	// Put writes a new item, which must not exist yet, like CreateItem.
+	Put Action = "put"
+	// Edit writes the changes made to an item as long as it is unchanged since it was read, like ReplaceItem.
+	Edit Action = "edit"
+	// Delete deletes an item, which must exist.
+	Delete Action = "delete"
+)
+
+// StagedChange is a write waiting to be committed along with others in a transaction.
+type StagedChange struct {
+	Action Action
+	Schema TableSchema
+	// Item is the item to write for puts and edits, and the item to delete for deletes.
+	Item Item
+	// Original is the item as it was read, for edits.
+	Original Item
+}
+
+// String describes the change by its action, table and key.
+func (c StagedChange) String() string {
+	b, err := c.Schema.KeyOf(c.Item).MarshalJSON()
+	if err != nil {
+		return fmt.Sprintf("%s in %s", c.Action, c.Schema.Name)
+	}
+	return fmt.Sprintf("%s in %s %s", c.Action, c.Schema.Name, b)
+}
+
+// StageEdit stages replacing original with updated, with the version incremented as ReplaceItem does.
+func StageEdit(schema TableSchema, original, updated Item) (StagedChange, error) {
+	item, err := prepareReplace(schema, original, updated)
+	if err != nil {
+		return StagedChange{}, err
+	}
+	return StagedChange{Action: Edit, Schema: schema, Item: item, Original: original.Copy()}, nil
+}
+
+// CancellationReason is why DynamoDB cancelled a transaction, for a single change.
+type CancellationReason struct {
+	// Code is None for changes which didn't cause the cancellation.
+	Code    string
+	Message string
+}
+
+// TransactionError is returned when a transaction is cancelled, with a reason for each of its changes.
+type TransactionError struct {
+	Changes []StagedChange
+	// Reasons are in the same order as Changes.
+	Reasons []CancellationReason
+}
+
+// Reason returns why change i caused the transaction to be cancelled, or nil if it didn't.  Failed conditions are
+// ErrExists for puts, ErrConflict for edits and ErrNotFound for deletes.
+func (e *TransactionError) Reason(i int) error {
+	if i >= len(e.Reasons) {
+		return nil
+	}
+	r := e.Reasons[i]
+	switch r.Code {
+	case "", "None":
+		return nil
+	case "ConditionalCheckFailed":
+		switch e.Changes[i].Action {
+		case Edit:
+			return ErrConflict
+		case Put:
+			return ErrExists
+		case Delete:
+			return ErrNotFound
+		}
+	case "TransactionConflict":
+		return errors.New("another transaction is writing the item")
+	}
+	if r.Message == "" {
+		return errors.New(r.Code)
+	}
+	return fmt.Errorf("%s: %s", r.Code, r.Message)
+}
+
+func (e *TransactionError) Error() string {
+	var failed []string
+	for i, c := range e.Changes {
+		if err := e.Reason(i); err != nil {
+			failed = append(failed, fmt.Sprintf("change %d, %s: %v", i+1, c, err))
+		}
+	}
+	if len(failed) == 0 {
+		return "transaction cancelled"
+	}
+	return "transaction cancelled: " + strings.Join(failed, "; ")
+}
+
+// checkTransaction validates changes before they are sent.  DynamoDB rejects transactions which touch the same item
+// twice.
+func checkTransaction(changes []StagedChange) error {
+	if len(changes) == 0 {
+		return errors.New("no changes to commit")
+	}
+	if len(changes) > MaxTransactionChanges {
+		return fmt.Errorf("%d changes is more than the %d allowed in a transaction", len(changes), MaxTransactionChanges)
+	}
+	for i, a := range changes {
+		key := a.Schema.KeySchema
+		if a.Item[key.HashKey] == nil || (key.RangeKey != "" && a.Item[key.RangeKey] == nil) {
+			return fmt.Errorf("%s is missing key attributes %s", a, keyNames(key))
+		}
+		for _, b := range changes[:i] {
+			if a.Schema.Name == b.Schema.Name && a.Schema.SameKey(a.Item, b.Item) {
+				return fmt.Errorf("%s and %s change the same item, only one change per item is allowed", b, a)
+			}
+		}
+	}
+	return nil
+}
+
+// Transact commits changes atomically, so either all of them are written or none are.  A *TransactionError is
+// returned when DynamoDB cancels the transaction.
+func (db *DB) Transact(ctx context.Context, changes []StagedChange) error {
+	if err := checkTransaction(changes); err != nil {
+		return err
+	}
+
+	tx := db.dynDB.WriteTx()
+	for _, c := range changes {
+		table := db.dynDB.Table(c.Schema.Name)
+		switch c.Action {
+		case Put:
+			tx.Put(table.Put(c.Item).If("attribute_not_exists($)", c.Schema.HashKey))
+		case Edit:
+			if set, removed := c.Schema.changedAttributes(c.Original, c.Item); len(set) > 0 || len(removed) > 0 {
+				tx.Update(db.updateChanged(c.Schema, c.Original, c.Item))
+				continue
+			}
+			// transactions can't hold updates which change nothing, so an unchanged item is only checked
+			check := table.Check(c.Schema.HashKey, c.Item[c.Schema.HashKey])
+			if c.Schema.RangeKey != "" {
+				check = check.Range(c.Schema.RangeKey, c.Item[c.Schema.RangeKey])
+			}
+			check = check.If("attribute_exists($)", c.Schema.HashKey)
+			for name, av := range unchangedConditions(c.Original) {
+				check = check.If("$ = ?", name, av)
+			}
+			tx.Check(check)
+		case Delete:
+			del := table.Delete(c.Schema.HashKey, c.Item[c.Schema.HashKey])
+			if c.Schema.RangeKey != "" {
+				del = del.Range(c.Schema.RangeKey, c.Item[c.Schema.RangeKey])
+			}
+			tx.Delete(del.If("attribute_exists($)", c.Schema.HashKey))
+		default:
+			return fmt.Errorf("unknown action %q", c.Action)
+		}
+	}
+
+	err := tx.RunWithContext(ctx)
+	var cancelled *ddb.TransactionCanceledException
+	if errors.As(err, &cancelled) {
+		txErr := &TransactionError{Changes: changes}
+		for _, r := range cancelled.CancellationReasons {
+			txErr.Reasons = append(txErr.Reasons, CancellationReason{Code: aws.StringValue(r.Code), Message: aws.StringValue(r.Message)})
+		}
+		return txErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Transact commits changes atomically, so either all of them are written or none are.  Conditions are checked as
+// DynamoDB would, and a *TransactionError is returned if any of them fail.
+func (db *MemoryDB) Transact(ctx context.Context, changes []StagedChange) error {
+	if err := checkTransaction(changes); err != nil {
+		return err
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	txErr := &TransactionError{Changes: changes}
+	cancelled := false
+	for _, c := range changes {
+		t, err := db.table(c.Schema.Name)
+		if err != nil {
+			return err
+		}
+		reason := CancellationReason{Code: "None"}
+		i := t.find(c.Item)
+		switch {
+		case c.Action == Put && i >= 0:
+			reason = CancellationReason{Code: "ConditionalCheckFailed", Message: "The conditional request failed"}
+		case c.Action == Edit && (i < 0 || !matchesStored(t.items[i], c.Original)):
+			reason = CancellationReason{Code: "ConditionalCheckFailed", Message: "The conditional request failed"}
+		case c.Action == Delete && i < 0:
+			reason = CancellationReason{Code: "ConditionalCheckFailed", Message: "The conditional request failed"}
+		}
+		if reason.Code != "None" {
+			cancelled = true
+		}
+		txErr.Reasons = append(txErr.Reasons, reason)
+	}
+	if cancelled {
+		return txErr
+	}
+
+	for _, c := range changes {
+		t, _ := db.table(c.Schema.Name)
+		i := t.find(c.Item)
+		switch c.Action {
+		case Put:
+			t.record(nil, c.Item)
+			t.items = append(t.items, c.Item.Copy())
+		case Edit:
+			written := c.Schema.applyChanges(t.items[i], c.Original, c.Item)
+			t.record(t.items[i], written)
+			t.items[i] = written
+		case Delete:
+			t.record(t.items[i], nil)
+			t.items = append(t.items[:i], t.items[i+1:]...)
+		}
+	}
+	return nil
+}
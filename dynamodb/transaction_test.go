@@ -0,0 +1,102 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryDBTransact(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	integrations, err := db.Schema(ctx, "dev-integrations")
+	require.NoError(t, err)
+	mappings, err := db.Schema(ctx, "dev-company-mappings")
+	require.NoError(t, err)
+
+	key := mustDecodeItem(t, `{"integration_id": "int-salesforce-001"}`)
+	read, err := db.GetItems(ctx, integrations, []Item{key})
+	require.NoError(t, err)
+	updated := read[0].Copy()
+	updated["name"] = mustDecodeItem(t, `{"name": "Salesforce"}`)["name"]
+	edit, err := StageEdit(integrations, read[0], updated)
+	require.NoError(t, err)
+	put := StagedChange{Action: Put, Schema: mappings, Item: mustDecodeItem(t, `{"company_id": "globex", "integration_id": "int-salesforce-001"}`)}
+	missing := StagedChange{Action: Delete, Schema: mappings, Item: mustDecodeItem(t, `{"company_id": "nobody", "integration_id": "int-none"}`)}
+
+	// a failed condition cancels everything, with the reason mapped to the change
+	err = db.Transact(ctx, []StagedChange{edit, put, missing})
+	var txErr *TransactionError
+	require.True(t, errors.As(err, &txErr))
+	require.NoError(t, txErr.Reason(0))
+	require.NoError(t, txErr.Reason(1))
+	require.True(t, errors.Is(txErr.Reason(2), ErrNotFound))
+	got, err := db.GetItems(ctx, mappings, []Item{put.Item})
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	// without it the rest commit together
+	require.NoError(t, db.Transact(ctx, []StagedChange{edit, put}))
+	got, err = db.GetItems(ctx, mappings, []Item{put.Item})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	stored, err := db.GetItems(ctx, integrations, []Item{key})
+	require.NoError(t, err)
+	require.Equal(t, "Salesforce", *stored[0]["name"].S)
+
+	// the same edit again conflicts, since the version has moved on
+	err = db.Transact(ctx, []StagedChange{edit})
+	require.True(t, errors.As(err, &txErr))
+	require.True(t, errors.Is(txErr.Reason(0), ErrConflict))
+
+	// so does putting an item which now exists
+	err = db.Transact(ctx, []StagedChange{put})
+	require.True(t, errors.As(err, &txErr))
+	require.True(t, errors.Is(txErr.Reason(0), ErrExists))
+
+	// changing the same item twice is rejected before anything is sent
+	err = db.Transact(ctx, []StagedChange{put, put})
+	require.Error(t, err)
+	require.False(t, errors.As(err, &txErr))
+}
+
+func TestDBTransactCancellationReasons(t *testing.T) {
+	t.Parallel()
+
+	// a stand in for DynamoDB which cancels every transaction because of its second change
+	db := newTestDB(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type": "com.amazonaws.dynamodb.v20120810#TransactionCanceledException", "message": "Transaction cancelled",
+			"CancellationReasons": [{"Code": "None"}, {"Code": "ConditionalCheckFailed", "Message": "The conditional request failed"}]}`))
+	})
+
+	schema := TableSchema{Name: "dev-integrations", KeySchema: KeySchema{HashKey: "integration_id", HashKeyType: "S"}}
+	original := mustDecodeItem(t, `{"integration_id": "a", "version": 1}`)
+	edit, err := StageEdit(schema, original, original)
+	require.NoError(t, err)
+	changes := []StagedChange{
+		{Action: Put, Schema: schema, Item: mustDecodeItem(t, `{"integration_id": "b"}`)},
+		edit,
+	}
+
+	err = db.Transact(context.Background(), changes)
+	var txErr *TransactionError
+	require.True(t, errors.As(err, &txErr))
+	require.NoError(t, txErr.Reason(0))
+	require.True(t, errors.Is(txErr.Reason(1), ErrConflict))
+	require.Contains(t, err.Error(), `change 2, edit in dev-integrations {"integration_id":"a"}`)
+}
+
+func mustDecodeItem(t *testing.T, s string) Item {
+	t.Helper()
+	i, err := DecodeItem([]byte(s))
+	require.NoError(t, err)
+	return i
+}
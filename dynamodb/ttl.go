@@ -0,0 +1,77 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// TimeToLive is the TTL configuration of a table.  Items are deleted some time after the time in their TTL attribute
+// has passed, which can take days.  Until then expired items are still returned by reads.
+type TimeToLive struct {
+	// Attribute holds the time each item expires at, in seconds since the epoch.
+	Attribute string
+	// Status is one of ENABLED, ENABLING, DISABLING or DISABLED.
+	Status string
+}
+
+// Enabled returns true when items are being deleted as they expire.
+func (t TimeToLive) Enabled() bool {
+	return t.Attribute != "" && (t.Status == ddb.TimeToLiveStatusEnabled || t.Status == ddb.TimeToLiveStatusEnabling)
+}
+
+// ExpiresAt returns the time item expires.  False is returned when TTL is not enabled or the item has no numeric
+// TTL attribute, in which case it never expires.
+func (t TimeToLive) ExpiresAt(item Item) (time.Time, bool) {
+	if !t.Enabled() {
+		return time.Time{}, false
+	}
+	av := item[t.Attribute]
+	if av == nil || av.N == nil {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseFloat(*av.N, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+// Expired returns true when item expired before now but may not have been deleted yet.
+func (t TimeToLive) Expired(item Item, now time.Time) bool {
+	at, ok := t.ExpiresAt(item)
+	return ok && at.Before(now)
+}
+
+// TimeToLive reads the TTL configuration of a table.
+func (db *DB) TimeToLive(ctx context.Context, table string) (TimeToLive, error) {
+	out, err := db.dynDB.Client().DescribeTimeToLiveWithContext(ctx, &ddb.DescribeTimeToLiveInput{TableName: aws.String(table)})
+	if err != nil {
+		return TimeToLive{}, fmt.Errorf("failed to describe time to live of %s: %w", table, err)
+	}
+	if out.TimeToLiveDescription == nil {
+		return TimeToLive{Status: ddb.TimeToLiveStatusDisabled}, nil
+	}
+	return TimeToLive{
+		Attribute: aws.StringValue(out.TimeToLiveDescription.AttributeName),
+		Status:    aws.StringValue(out.TimeToLiveDescription.TimeToLiveStatus),
+	}, nil
+}
+
+// TimeToLive returns the TTL configuration of a table, as given by its fixture.  Expired items are never deleted.
+func (db *MemoryDB) TimeToLive(ctx context.Context, table string) (TimeToLive, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	t, err := db.table(table)
+	if err != nil {
+		return TimeToLive{}, err
+	}
+	if t.ttl == "" {
+		return TimeToLive{Status: ddb.TimeToLiveStatusDisabled}, nil
+	}
+	return TimeToLive{Attribute: t.ttl, Status: ddb.TimeToLiveStatusEnabled}, nil
+}
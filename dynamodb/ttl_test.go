@@ -0,0 +1,69 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeToLive(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewMemoryDBFromFile(testFixtures)
+	require.NoError(t, err)
+	ttl, err := db.TimeToLive(context.Background(), "dev-integration-sessions")
+	require.NoError(t, err)
+	require.Equal(t, TimeToLive{Attribute: "expires_at", Status: "ENABLED"}, ttl)
+	disabled, err := db.TimeToLive(context.Background(), "dev-integrations")
+	require.NoError(t, err)
+	require.False(t, disabled.Enabled())
+
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name    string
+		ttl     TimeToLive
+		item    string
+		expires bool
+		expired bool
+	}{
+		{
+			name:    "expired",
+			ttl:     ttl,
+			item:    `{"session_id": "a", "expires_at": 1600000000}`,
+			expires: true,
+			expired: true,
+		},
+		{
+			name:    "expires later",
+			ttl:     ttl,
+			item:    `{"session_id": "a", "expires_at": 1800000000}`,
+			expires: true,
+		},
+		{
+			name: "no ttl attribute",
+			ttl:  ttl,
+			item: `{"session_id": "a"}`,
+		},
+		{
+			name: "ttl attribute is not a number",
+			ttl:  ttl,
+			item: `{"session_id": "a", "expires_at": "tomorrow"}`,
+		},
+		{
+			name: "ttl disabled",
+			ttl:  TimeToLive{Attribute: "expires_at", Status: "DISABLED"},
+			item: `{"session_id": "a", "expires_at": 1600000000}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := mustDecodeItem(t, tt.item)
+			_, ok := tt.ttl.ExpiresAt(item)
+			require.Equal(t, tt.expires, ok)
+			require.Equal(t, tt.expired, tt.ttl.Expired(item, now))
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	ddb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// VersionKey is the attribute used for optimistic locking.  Items with a numeric version are only replaced when the
+// stored version matches the one read, and the version is incremented on every write.
+const VersionKey = "version"
+
+var (
+	// ErrConflict is returned when an item changed between being read and written.
+	ErrConflict = errors.New("item was changed by someone else since it was read")
+	// ErrExists is returned when creating an item which already exists.
+	ErrExists = errors.New("an item with the same key already exists")
+)
+
+// ReplaceItem writes updated over original, which must be the item as it was read.  Only the attributes which differ
+// from original are set or removed, so attributes added to the stored item since it was read are kept.  The write is
+// conditional on the stored item being unchanged: its version must match when it has one, otherwise every attribute
+// of original must match.  ErrConflict is returned if the item has changed.  The stored item is returned as written.
+func (db *DB) ReplaceItem(ctx context.Context, schema TableSchema, original, updated Item) (Item, error) {
+	item, err := prepareReplace(schema, original, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	var written Item
+	if err := db.updateChanged(schema, original, item).ValueWithContext(ctx, &written); err != nil {
+		if isConditionFailed(err) {
+			return nil, fmt.Errorf("failed to write item: %w", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to write item: %w", err)
+	}
+	return written, nil
+}
+
+// updateChanged builds an update of the stored item from original to item, conditional on it being unchanged since
+// original was read.
+func (db *DB) updateChanged(schema TableSchema, original, item Item) *dynamo.Update {
+	update := db.dynDB.Table(schema.Name).Update(schema.HashKey, item[schema.HashKey])
+	if schema.RangeKey != "" {
+		update = update.Range(schema.RangeKey, item[schema.RangeKey])
+	}
+	set, removed := schema.changedAttributes(original, item)
+	for name, av := range set {
+		update = update.SetExpr("$ = ?", name, av)
+	}
+	for _, name := range removed {
+		update = update.RemoveExpr("$", name)
+	}
+
+	update = update.If("attribute_exists($)", schema.HashKey)
+	for name, av := range unchangedConditions(original) {
+		update = update.If("$ = ?", name, av)
+	}
+	return update
+}
+
+// prepareReplace validates an edit and returns the item to write, with its version incremented.
+func prepareReplace(schema TableSchema, original, updated Item) (Item, error) {
+	if !schema.SameKey(original, updated) {
+		return nil, fmt.Errorf("key attributes %s cannot be edited", keyNames(schema.KeySchema))
+	}
+	item := updated.Copy()
+	if v := original[VersionKey]; v != nil && v.N != nil {
+		n, ok := new(big.Int).SetString(*v.N, 10)
+		if !ok {
+			return nil, fmt.Errorf("%s %s is not an integer", VersionKey, *v.N)
+		}
+		item[VersionKey] = &ddb.AttributeValue{N: aws.String(n.Add(n, big.NewInt(1)).String())}
+	}
+	return item, nil
+}
+
+// unchangedConditions returns the attributes that must be unchanged for original to be replaced: its version when it
+// has one, otherwise all of it.
+func unchangedConditions(original Item) Item {
+	if v := original[VersionKey]; v != nil && v.N != nil {
+		return Item{VersionKey: v}
+	}
+	return original
+}
+
+// changedAttributes returns the top level attributes of item which differ from original, and the names of those
+// removed from it, sorted.  Key attributes are never among them, since they can't be updated, even when written
+// differently for the same key, like "1.0" for "1".
+func (k KeySchema) changedAttributes(original, item Item) (set Item, removed []string) {
+	set = make(Item)
+	for name, av := range item {
+		if name == k.HashKey || name == k.RangeKey {
+			continue
+		}
+		if !reflect.DeepEqual(original[name], av) {
+			set[name] = av
+		}
+	}
+	for name := range original {
+		if name == k.HashKey || name == k.RangeKey {
+			continue
+		}
+		if _, ok := item[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return set, removed
+}
+
+// applyChanges returns a copy of stored with the changes from original to item made to it, the way an update would.
+func (k KeySchema) applyChanges(stored, original, item Item) Item {
+	applied := stored.Copy()
+	set, removed := k.changedAttributes(original, item)
+	for name, av := range set {
+		applied[name] = copyAttr(av)
+	}
+	for _, name := range removed {
+		delete(applied, name)
+	}
+	return applied
+}
+
+// matchesStored reports whether the stored item still satisfies the unchanged conditions of original.
+func matchesStored(stored, original Item) bool {
+	for name, av := range unchangedConditions(original) {
+		if !reflect.DeepEqual(stored[name], av) {
+			return false
+		}
+	}
+	return true
+}
+
+// isConditionFailed reports whether err is the result of a failed write condition.
+func isConditionFailed(err error) bool {
+	var ae awserr.Error
+	return errors.As(err, &ae) && ae.Code() == ddb.ErrCodeConditionalCheckFailedException
+}
+
+// keyNames describes the key attributes of a schema.
+func keyNames(k KeySchema) string {
+	if k.RangeKey == "" {
+		return k.HashKey
+	}
+	return k.HashKey + " and " + k.RangeKey
+}
+
+// DeleteItem deletes the item with the same key as item, returning the item as it was stored.  ErrNotFound is
+// returned if there is no such item.
+func (db *DB) DeleteItem(ctx context.Context, schema TableSchema, item Item) (Item, error) {
+	del := db.dynDB.Table(schema.Name).Delete(schema.HashKey, item[schema.HashKey])
+	if schema.RangeKey != "" {
+		del = del.Range(schema.RangeKey, item[schema.RangeKey])
+	}
+	var old Item
+	err := del.OldValueWithContext(ctx, &old)
+	if err == dynamo.ErrNotFound {
+		return nil, fmt.Errorf("failed to delete item: %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete item: %w", err)
+	}
+	return old, nil
+}
+
+// CreateItem writes item as long as no item with the same key exists.  ErrExists is returned if one does.
+func (db *DB) CreateItem(ctx context.Context, schema TableSchema, item Item) error {
+	err := db.dynDB.Table(schema.Name).Put(item).If("attribute_not_exists($)", schema.HashKey).RunWithContext(ctx)
+	if isConditionFailed(err) {
+		return fmt.Errorf("failed to create item: %w", ErrExists)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create item: %w", err)
+	}
+	return nil
+}
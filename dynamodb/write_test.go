@@ -0,0 +1,128 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBReplaceItem(t *testing.T) {
+	t.Parallel()
+
+	// a stand in for DynamoDB which records the update expression of every write, with placeholders swapped for
+	// names, and answers with an item someone else has since added an attribute to
+	var lock sync.Mutex
+	var updates []string
+	var decodeErr error
+	placeholder := regexp.MustCompile(`#\w+`)
+	db := newTestDB(t, func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			UpdateExpression         string
+			ExpressionAttributeNames map[string]string
+		}
+		err := json.NewDecoder(r.Body).Decode(&in)
+		lock.Lock()
+		if err != nil {
+			decodeErr = err
+		}
+		updates = append(updates, placeholder.ReplaceAllStringFunc(in.UpdateExpression, func(s string) string {
+			return in.ExpressionAttributeNames[s]
+		}))
+		lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{"Attributes": {"integration_id": {"S": "a"}, "name": {"S": "Slack Pages"}, "version": {"N": "2"}, "added": {"S": "by someone else"}}}`))
+	})
+
+	schema := TableSchema{Name: "dev-integrations", KeySchema: KeySchema{HashKey: IntegrationIDKey, HashKeyType: "S"}}
+	original := mustDecodeItem(t, `{"integration_id": "a", "name": "Slack", "note": "old", "config": {"channel": "#alerts"}, "version": 1}`)
+	edited := mustDecodeItem(t, `{"integration_id": "a", "name": "Slack Pages", "config": {"channel": "#alerts"}, "version": 1}`)
+	written, err := db.ReplaceItem(context.Background(), schema, original, edited)
+	require.NoError(t, err)
+	require.Equal(t, "by someone else", *written["added"].S)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.NoError(t, decodeErr)
+	require.Len(t, updates, 1)
+
+	// only the changed attributes are set or removed, so everything else stored is left alone
+	parts := strings.SplitN(strings.TrimPrefix(updates[0], "SET "), " REMOVE ", 2)
+	require.Len(t, parts, 2)
+	var set []string
+	for _, s := range strings.Split(parts[0], ", ") {
+		set = append(set, strings.SplitN(s, " = ", 2)[0])
+	}
+	sort.Strings(set)
+	require.Equal(t, []string{"name", "version"}, set)
+	require.Equal(t, "note", parts[1])
+}
+
+func TestDBReplaceItemNumericKey(t *testing.T) {
+	t.Parallel()
+
+	// a stand in for DynamoDB which records the update expression of every write, with placeholders swapped for names
+	var lock sync.Mutex
+	var updates []string
+	placeholder := regexp.MustCompile(`#\w+`)
+	db := newTestDB(t, func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			UpdateExpression         string
+			ExpressionAttributeNames map[string]string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err == nil {
+			lock.Lock()
+			updates = append(updates, placeholder.ReplaceAllStringFunc(in.UpdateExpression, func(s string) string {
+				return in.ExpressionAttributeNames[s]
+			}))
+			lock.Unlock()
+		}
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{"Attributes": {"id": {"N": "1"}, "at": {"N": "2"}, "name": {"S": "Slack Pages"}}}`))
+	})
+
+	// DynamoDB rejects updates which set key attributes, even to the same number
+	schema := TableSchema{Name: "dev-counters", KeySchema: KeySchema{HashKey: "id", HashKeyType: "N", RangeKey: "at", RangeKeyType: "N"}}
+	original := mustDecodeItem(t, `{"id": 1, "at": 2, "name": "Slack"}`)
+	edited := mustDecodeItem(t, `{"id": 1.0, "at": 2.00, "name": "Slack Pages"}`)
+	_, err := db.ReplaceItem(context.Background(), schema, original, edited)
+	require.NoError(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, []string{"SET name = :v0"}, updates)
+}
+
+func TestChangedAttributes(t *testing.T) {
+	t.Parallel()
+
+	key := KeySchema{HashKey: "id", HashKeyType: "S"}
+	original := mustDecodeItem(t, `{"id": "a", "name": "Slack", "note": "old", "config": {"channel": "#alerts"}}`)
+	item := mustDecodeItem(t, `{"id": "a", "name": "Slack", "config": {"channel": "#pages"}, "steps": [1]}`)
+	set, removed := key.changedAttributes(original, item)
+	require.Equal(t, mustDecodeItem(t, `{"config": {"channel": "#pages"}, "steps": [1]}`), set)
+	require.Equal(t, []string{"note"}, removed)
+
+	// the changes are made over whatever is stored now
+	stored := original.Copy()
+	stored["added"] = mustDecodeItem(t, `{"added": true}`)["added"]
+	require.Equal(t, mustDecodeItem(t, `{"id": "a", "name": "Slack", "config": {"channel": "#pages"}, "steps": [1], "added": true}`), key.applyChanges(stored, original, item))
+	require.Contains(t, stored.Names(), "note")
+
+	// keys written differently for the same number are the same key, which is never set
+	numeric := KeySchema{HashKey: "id", HashKeyType: "N", RangeKey: "at", RangeKeyType: "N"}
+	original = mustDecodeItem(t, `{"id": 1, "at": 2, "name": "Slack"}`)
+	item = mustDecodeItem(t, `{"id": 1.0, "at": 2.00, "name": "Slack Pages"}`)
+	set, removed = numeric.changedAttributes(original, item)
+	require.Equal(t, mustDecodeItem(t, `{"name": "Slack Pages"}`), set)
+	require.Empty(t, removed)
+	require.Equal(t, mustDecodeItem(t, `{"id": 1, "at": 2, "name": "Slack Pages"}`), numeric.applyChanges(original, original, item))
+}
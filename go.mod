@@ -3,10 +3,9 @@ module github.com/swtch1/tbdui
 go 1.14
 
 require (
-	github.com/aws/aws-sdk-go v1.33.12
+	github.com/aws/aws-sdk-go v1.36.30
 	github.com/gizak/termui/v3 v3.1.0
 	github.com/guregu/dynamo v1.8.0
 	github.com/sirupsen/logrus v1.6.0
 	github.com/stretchr/testify v1.5.1
-	gopkg.in/yaml.v2 v2.2.4 // indirect
 )